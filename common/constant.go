@@ -45,4 +45,42 @@ const (
 	ErrErrorGeneratingToken       = "ERROR_GENERATING_TOKEN"
 	ErrDatabaseSaveFailed         = "DATABASE_SAVE_FAILED"
 	ErrRoleIDRetrievalFailed      = "ROLE_ID_RETRIEVAL_FAILED"
+	ErrInvalidFilter              = "INVALID_FILTER"
+	ErrInvalidSort                = "INVALID_SORT"
+	ErrInvalidServiceLine         = "INVALID_SERVICE_LINE"
+	ErrTotalMismatch              = "TOTAL_MISMATCH"
+	ErrAmountOutOfRange           = "AMOUNT_OUT_OF_RANGE"
+	ErrInvalidTax                 = "INVALID_TAX"
+	ErrNumberingNotConfigured     = "NUMBERING_NOT_CONFIGURED"
+	ErrNumberingExpired           = "NUMBERING_EXPIRED"
+	ErrNumberingExhausted         = "NUMBERING_EXHAUSTED"
+	ErrInvalidNumbering           = "INVALID_NUMBERING"
+	ErrXMLGenerationFailed        = "XML_GENERATION_FAILED"
+	ErrInvalidDocument            = "INVALID_DOCUMENT"
+	ErrInvalidStatusTransition    = "INVALID_STATUS_TRANSITION"
+	ErrInvoiceNotEditable         = "INVOICE_NOT_EDITABLE"
+	ErrInvalidNote                = "INVALID_NOTE"
+	ErrNoteNotAllowed             = "NOTE_NOT_ALLOWED"
+	ErrNoteExceedsBalance         = "NOTE_EXCEEDS_BALANCE"
+	ErrNoteNotFound               = "NOTE_NOT_FOUND"
+	ErrInvalidPayment             = "INVALID_PAYMENT"
+	ErrPaymentNotAllowed          = "PAYMENT_NOT_ALLOWED"
+	ErrPaymentExceedsBalance      = "PAYMENT_EXCEEDS_BALANCE"
+	ErrPaymentNotFound            = "PAYMENT_NOT_FOUND"
+	ErrPaymentAlreadyReversed     = "PAYMENT_ALREADY_REVERSED"
+	ErrInvalidCompany             = "INVALID_COMPANY"
+	ErrCompanyNotFound            = "COMPANY_NOT_FOUND"
+	ErrCompanyAlreadyExists       = "COMPANY_ALREADY_EXISTS"
+	ErrInvalidProfile             = "INVALID_PROFILE"
+	ErrInvalidCatalogItem         = "INVALID_CATALOG_ITEM"
+	ErrCatalogItemNotFound        = "CATALOG_ITEM_NOT_FOUND"
+	ErrCatalogItemAlreadyExists   = "CATALOG_ITEM_ALREADY_EXISTS"
+	ErrInvalidPDFTemplate         = "INVALID_PDF_TEMPLATE"
+	ErrPDFTemplateNotFound        = "PDF_TEMPLATE_NOT_FOUND"
+	ErrPDFTemplateAlreadyExists   = "PDF_TEMPLATE_ALREADY_EXISTS"
+	ErrInvalidProfileImage        = "INVALID_PROFILE_IMAGE"
+	ErrProfileImageNotFound       = "PROFILE_IMAGE_NOT_FOUND"
+	ErrExportNotFound             = "EXPORT_NOT_FOUND"
+	ErrExportNotReady             = "EXPORT_NOT_READY"
+	ErrPDFGenerationFailed        = "PDF_GENERATION_FAILED"
 )
@@ -4,12 +4,9 @@ import (
 	"database/sql"
 	interfaceDB "facturaexpress/interfaces"
 	"fmt"
-	"log"
 	"os"
 	"strconv"
-	"sync"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
@@ -20,52 +17,51 @@ type PostgresAdapter struct {
 // implemeto la interfaz Database
 var _ interfaceDB.Database = &PostgresAdapter{}
 
-var instance *PostgresAdapter
-var once sync.Once
-
-func GetInstance() *PostgresAdapter {
-	once.Do(func() {
-		err := godotenv.Load(".env")
-		if err != nil {
-			log.Fatalf("Error loading .env file")
-		}
-
-		host := os.Getenv("DB_HOST")
-		port, err := strconv.Atoi(os.Getenv("DB_PORT"))
-		if err != nil {
-			panic("Error: DB_PORT must be an integer")
-		}
-		user := os.Getenv("DB_USER")
-		password := os.Getenv("DB_PASSWORD")
-		dbname := os.Getenv("DB_NAME")
-
-		psqlInfo := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-			host, port, user, password, dbname)
-
-		db, err := sql.Open("postgres", psqlInfo)
-		if err != nil {
-			panic(err)
-		}
-
-		err = db.Ping()
-		if err != nil {
-			panic(err)
-		}
-
-		instance = &PostgresAdapter{conn: db}
-	})
-	return instance
+// Config contiene los parámetros de conexión a PostgreSQL.
+type Config struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
 }
 
-func (db *PostgresAdapter) Prepare(query string) (*sql.Stmt, error) {
-	stmt, err := db.conn.Prepare(query)
+// ConfigFromEnv construye la configuración de conexión a partir de las variables DB_*.
+func ConfigFromEnv() (Config, error) {
+	port, err := strconv.Atoi(os.Getenv("DB_PORT"))
 	if err != nil {
-		return nil, fmt.Errorf("error al preparar la consulta: %v", err)
+		return Config{}, fmt.Errorf("DB_PORT debe ser un número entero")
 	}
-	return stmt, nil
+	return Config{
+		Host:     os.Getenv("DB_HOST"),
+		Port:     port,
+		User:     os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASSWORD"),
+		DBName:   os.Getenv("DB_NAME"),
+	}, nil
 }
 
-func (db *PostgresAdapter) Query(query string, args ...interface{}) (*sql.Rows, error) {
+// NewPostgresAdapter abre la conexión y verifica que la base de datos responda.
+// Se construye una sola vez en main y se inyecta en los controladores.
+func NewPostgresAdapter(config Config) (*PostgresAdapter, error) {
+	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		config.Host, config.Port, config.User, config.Password, config.DBName)
+
+	db, err := sql.Open("postgres", psqlInfo)
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir la conexión: %v", err)
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al conectar con la base de datos: %v", err)
+	}
+
+	return &PostgresAdapter{conn: db}, nil
+}
+
+func (db *PostgresAdapter) Query(query string, args ...interface{}) (interfaceDB.Rows, error) {
 	rows, err := db.conn.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error al ejecutar la consulta: %v", err)
@@ -81,10 +77,45 @@ func (db *PostgresAdapter) Exec(query string, args ...interface{}) (sql.Result,
 	return result, nil
 }
 
-func (db *PostgresAdapter) QueryRow(query string, args ...interface{}) *sql.Row {
+func (db *PostgresAdapter) QueryRow(query string, args ...interface{}) interfaceDB.Row {
 	return db.conn.QueryRow(query, args...)
 }
 
+func (db *PostgresAdapter) Begin() (interfaceDB.Tx, error) {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error al iniciar la transacción: %v", err)
+	}
+	return postgresTx{tx}, nil
+}
+
 func (db *PostgresAdapter) Close() error {
 	return db.conn.Close()
 }
+
+// postgresTx adapta *sql.Tx a la interfaz Tx.
+type postgresTx struct {
+	tx *sql.Tx
+}
+
+var _ interfaceDB.Tx = postgresTx{}
+
+func (t postgresTx) Query(query string, args ...interface{}) (interfaceDB.Rows, error) {
+	return t.tx.Query(query, args...)
+}
+
+func (t postgresTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.tx.Exec(query, args...)
+}
+
+func (t postgresTx) QueryRow(query string, args ...interface{}) interfaceDB.Row {
+	return t.tx.QueryRow(query, args...)
+}
+
+func (t postgresTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t postgresTx) Rollback() error {
+	return t.tx.Rollback()
+}
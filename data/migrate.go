@@ -0,0 +1,246 @@
+package data
+
+import (
+	"embed"
+	interfaceDB "facturaexpress/interfaces"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Las migraciones viajan dentro del binario para que un despliegue nuevo no
+// dependa de crear las tablas a mano desde pgAdmin.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID identifica el candado consultivo de PostgreSQL que evita que dos instancias
+// apliquen migraciones al mismo tiempo.
+const migrationLockID = 7204190411
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// LoadMigrations lee los archivos NNNN_nombre.up.sql / NNNN_nombre.down.sql
+// embebidos y los devuelve ordenados por versión.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error al leer las migraciones: %v", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionStr, name, found := strings.Cut(base, "_")
+		if !found {
+			return nil, fmt.Errorf("nombre de migración inválido: %s", fileName)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("versión de migración inválida: %s", fileName)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", fileName))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer la migración %s: %v", fileName, err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("la migración %04d_%s debe tener archivos up y down", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrateUp aplica, en orden, todas las migraciones pendientes y devuelve las que se aplicaron.
+func MigrateUp(db interfaceDB.Database) ([]Migration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		alreadyApplied := false
+		err := runInTx(db, func(tx interfaceDB.Tx) error {
+			// Otra instancia pudo aplicarla mientras esperábamos el candado
+			if err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)`, migration.Version).Scan(&alreadyApplied); err != nil || alreadyApplied {
+				return err
+			}
+			if _, err := tx.Exec(migration.Up); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`INSERT INTO schema_migrations (version, nombre) VALUES ($1, $2)`, migration.Version, migration.Name)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("error al aplicar la migración %04d_%s: %v", migration.Version, migration.Name, err)
+		}
+		if !alreadyApplied {
+			done = append(done, migration)
+		}
+	}
+	return done, nil
+}
+
+// MigrateDown revierte la última migración aplicada. Devuelve nil si no hay nada que revertir.
+func MigrateDown(db interfaceDB.Database) (*Migration, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+
+	var last int
+	err = db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&last)
+	if err != nil {
+		return nil, fmt.Errorf("error al consultar las migraciones aplicadas: %v", err)
+	}
+	if last == 0 {
+		return nil, nil
+	}
+
+	for _, migration := range migrations {
+		if migration.Version != last {
+			continue
+		}
+		err := runInTx(db, func(tx interfaceDB.Tx) error {
+			if _, err := tx.Exec(migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error al revertir la migración %04d_%s: %v", migration.Version, migration.Name, err)
+		}
+		return &migration, nil
+	}
+	return nil, fmt.Errorf("la migración aplicada %04d no existe en este binario", last)
+}
+
+// GetMigrationStatus indica, para cada migración embebida, si ya fue aplicada y cuándo.
+func GetMigrationStatus(db interfaceDB.Database) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		entry := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			entry.Applied = true
+			entry.AppliedAt = &appliedAt
+		}
+		status = append(status, entry)
+	}
+	return status, nil
+}
+
+func ensureMigrationsTable(db interfaceDB.Database) error {
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		nombre TEXT NOT NULL,
+		aplicada_en TIMESTAMP NOT NULL DEFAULT NOW()
+	)`)
+	return err
+}
+
+func appliedMigrations(db interfaceDB.Database) (map[int]time.Time, error) {
+	rows, err := db.Query(`SELECT version, aplicada_en FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error al leer las migraciones aplicadas: %v", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// runInTx ejecuta fn dentro de una transacción que además toma el candado de migraciones.
+func runInTx(db interfaceDB.Database, fn func(tx interfaceDB.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
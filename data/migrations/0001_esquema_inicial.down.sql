@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS facturas;
+DROP TABLE IF EXISTS jwt_blacklist;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS usuarios;
@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS usuarios (
+    id SERIAL PRIMARY KEY,
+    nombre_usuario TEXT NOT NULL UNIQUE,
+    password TEXT NOT NULL,
+    correo TEXT NOT NULL UNIQUE,
+    jwt_token TEXT
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+
+CREATE TABLE IF NOT EXISTS jwt_blacklist (
+    id SERIAL PRIMARY KEY,
+    token TEXT NOT NULL UNIQUE,
+    creado_en TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS facturas (
+    id SERIAL PRIMARY KEY,
+    nombre_empresa TEXT NOT NULL,
+    nit_empresa TEXT NOT NULL,
+    fecha TIMESTAMP NOT NULL,
+    servicios JSONB NOT NULL,
+    valor_total NUMERIC NOT NULL,
+    nombre_operador TEXT NOT NULL DEFAULT '',
+    tipo_documento_operador TEXT NOT NULL DEFAULT '',
+    documento_operador TEXT NOT NULL DEFAULT '',
+    ciudad_expedicion_documento_operador TEXT NOT NULL DEFAULT '',
+    celular_operador TEXT NOT NULL DEFAULT '',
+    numero_cuenta_bancaria_operador TEXT NOT NULL DEFAULT '',
+    tipo_cuenta_bancaria_operador TEXT NOT NULL DEFAULT '',
+    banco_operador TEXT NOT NULL DEFAULT '',
+    usuario_id INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS facturas_usuario_id_idx ON facturas (usuario_id);
+
+INSERT INTO roles (name)
+SELECT nombre FROM (VALUES ('administrador'), ('usuario')) AS semilla (nombre)
+WHERE NOT EXISTS (SELECT 1 FROM roles WHERE roles.name = semilla.nombre);
@@ -0,0 +1,10 @@
+UPDATE facturas SET servicios = (
+    SELECT COALESCE(jsonb_agg(
+        jsonb_build_object(
+            'descripcion', servicio->'descripcion',
+            'valor', COALESCE(servicio->'subtotal', '0'::jsonb)
+        )
+        ORDER BY posicion), '[]'::jsonb)
+    FROM jsonb_array_elements(servicios) WITH ORDINALITY AS linea (servicio, posicion)
+)
+WHERE EXISTS (SELECT 1 FROM jsonb_array_elements(servicios) AS servicio WHERE servicio ? 'subtotal');
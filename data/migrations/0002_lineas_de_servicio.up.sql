@@ -0,0 +1,18 @@
+-- Los servicios pasan de {descripcion, valor} a líneas con cantidad, unidad,
+-- valor unitario, descuento y subtotal. Las facturas existentes quedan como
+-- una unidad por servicio al valor que tenían.
+UPDATE facturas SET servicios = (
+    SELECT COALESCE(jsonb_agg(
+        CASE WHEN servicio ? 'valor' THEN
+            (servicio - 'valor') || jsonb_build_object(
+                'cantidad', 1,
+                'unidad', '',
+                'valor_unitario', COALESCE(servicio->'valor', '0'::jsonb),
+                'descuento', 0,
+                'subtotal', COALESCE(servicio->'valor', '0'::jsonb)
+            )
+        ELSE servicio END
+        ORDER BY posicion), '[]'::jsonb)
+    FROM jsonb_array_elements(servicios) WITH ORDINALITY AS linea (servicio, posicion)
+)
+WHERE EXISTS (SELECT 1 FROM jsonb_array_elements(servicios) AS servicio WHERE servicio ? 'valor');
@@ -0,0 +1 @@
+ALTER TABLE facturas ALTER COLUMN valor_total TYPE NUMERIC;
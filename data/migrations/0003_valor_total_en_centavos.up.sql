@@ -0,0 +1,3 @@
+-- valor_total se maneja en la aplicación como centavos exactos (models.Money);
+-- la columna guarda exactamente dos decimales.
+ALTER TABLE facturas ALTER COLUMN valor_total TYPE NUMERIC(18, 2) USING ROUND(valor_total, 2);
@@ -0,0 +1,13 @@
+UPDATE facturas
+SET servicios = (
+    SELECT COALESCE(jsonb_agg(servicio - 'tarifa_iva' - 'valor_iva' ORDER BY posicion), '[]'::jsonb)
+    FROM jsonb_array_elements(servicios) WITH ORDINALITY AS lineas(servicio, posicion)
+);
+
+ALTER TABLE facturas
+    DROP COLUMN IF EXISTS retenciones,
+    DROP COLUMN IF EXISTS impuestos,
+    DROP COLUMN IF EXISTS subtotal,
+    DROP COLUMN IF EXISTS total_iva,
+    DROP COLUMN IF EXISTS total_retenciones,
+    DROP COLUMN IF EXISTS valor_neto;
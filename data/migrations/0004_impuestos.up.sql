@@ -0,0 +1,18 @@
+-- Impuestos y retenciones. retenciones guarda las retenciones solicitadas y
+-- impuestos las líneas calculadas (IVA por tarifa y cada retención practicada).
+ALTER TABLE facturas
+    ADD COLUMN IF NOT EXISTS retenciones JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS impuestos JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS subtotal NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS total_iva NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS total_retenciones NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS valor_neto NUMERIC(18, 2) NOT NULL DEFAULT 0;
+
+-- Las facturas existentes no tenían impuestos: todo el valor es base exenta
+UPDATE facturas SET subtotal = valor_total, valor_neto = valor_total;
+
+UPDATE facturas
+SET servicios = (
+    SELECT COALESCE(jsonb_agg(servicio || '{"tarifa_iva": "exento", "valor_iva": 0}'::jsonb ORDER BY posicion), '[]'::jsonb)
+    FROM jsonb_array_elements(servicios) WITH ORDINALITY AS lineas(servicio, posicion)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS facturas_usuario_numero_idx;
+ALTER TABLE facturas DROP COLUMN IF EXISTS numero;
+DROP TABLE IF EXISTS numeraciones;
@@ -0,0 +1,22 @@
+-- Numeración autorizada por la DIAN para cada emisor. consecutivo_actual es
+-- el último número asignado; se incrementa en la misma transacción que emite
+-- la factura (los borradores no tienen número) para que no queden huecos.
+CREATE TABLE IF NOT EXISTS numeraciones (
+    id SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL UNIQUE REFERENCES usuarios (id) ON DELETE CASCADE,
+    prefijo VARCHAR(4) NOT NULL DEFAULT '',
+    numero_resolucion TEXT NOT NULL,
+    fecha_resolucion DATE NOT NULL,
+    vigencia_desde DATE NOT NULL,
+    vigencia_hasta DATE NOT NULL,
+    rango_desde BIGINT NOT NULL,
+    rango_hasta BIGINT NOT NULL,
+    consecutivo_actual BIGINT NOT NULL,
+    CHECK (rango_desde > 0 AND rango_hasta >= rango_desde),
+    CHECK (consecutivo_actual BETWEEN rango_desde - 1 AND rango_hasta)
+);
+
+-- Las facturas anteriores a la numeración quedan sin número
+ALTER TABLE facturas ADD COLUMN IF NOT EXISTS numero TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS facturas_usuario_numero_idx ON facturas (usuario_id, numero) WHERE numero <> '';
@@ -0,0 +1,2 @@
+-- Código único de facturación electrónica (SHA-384 en hexadecimal)
+ALTER TABLE facturas ADD COLUMN IF NOT EXISTS cufe TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- La normalización de los tipos de documento no se puede revertir: los
+-- valores originales no se conservan.
+SELECT 1;
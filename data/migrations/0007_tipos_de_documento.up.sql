@@ -0,0 +1,17 @@
+-- Los tipos de documento del operador pasan a un conjunto fijo: CC, CE, NIT,
+-- PAS y TI. Los valores que no se reconocen se dejan como estaban.
+UPDATE facturas
+SET tipo_documento_operador = CASE
+    WHEN tipo IN ('CC', 'CEDULA', 'CEDULADECIUDADANIA') THEN 'CC'
+    WHEN tipo IN ('CE', 'CEDULADEEXTRANJERIA') THEN 'CE'
+    WHEN tipo = 'NIT' THEN 'NIT'
+    WHEN tipo IN ('PAS', 'PA', 'PP', 'PASAPORTE') THEN 'PAS'
+    WHEN tipo IN ('TI', 'TARJETADEIDENTIDAD') THEN 'TI'
+    ELSE tipo_documento_operador
+END
+FROM (
+    SELECT id AS factura_id,
+        translate(upper(tipo_documento_operador), 'ÉÍ. -_', 'EI') AS tipo
+    FROM facturas
+) AS normalizados
+WHERE facturas.id = normalizados.factura_id;
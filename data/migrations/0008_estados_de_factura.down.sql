@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS factura_eventos;
+DROP INDEX IF EXISTS facturas_estado_idx;
+ALTER TABLE facturas DROP COLUMN IF EXISTS estado;
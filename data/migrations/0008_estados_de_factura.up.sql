@@ -0,0 +1,20 @@
+-- Ciclo de vida de las facturas. Las facturas existentes ya se entregaron,
+-- así que quedan emitidas; las nuevas empiezan como borrador.
+ALTER TABLE facturas ADD COLUMN IF NOT EXISTS estado TEXT NOT NULL DEFAULT 'emitida'
+    CHECK (estado IN ('borrador', 'emitida', 'enviada', 'pagada', 'anulada'));
+ALTER TABLE facturas ALTER COLUMN estado SET DEFAULT 'borrador';
+
+CREATE INDEX IF NOT EXISTS facturas_estado_idx ON facturas (estado);
+
+-- Cada cambio de estado con su motivo y su fecha
+CREATE TABLE IF NOT EXISTS factura_eventos (
+    id SERIAL PRIMARY KEY,
+    factura_id INTEGER NOT NULL REFERENCES facturas (id) ON DELETE CASCADE,
+    estado_anterior TEXT NOT NULL,
+    estado_nuevo TEXT NOT NULL,
+    motivo TEXT NOT NULL DEFAULT '',
+    usuario_id INTEGER REFERENCES usuarios (id) ON DELETE SET NULL,
+    creado_en TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS factura_eventos_factura_idx ON factura_eventos (factura_id);
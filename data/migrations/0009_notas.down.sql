@@ -0,0 +1,5 @@
+ALTER TABLE facturas
+    DROP COLUMN IF EXISTS total_notas_credito,
+    DROP COLUMN IF EXISTS total_notas_debito;
+DROP TABLE IF EXISTS consecutivos_notas;
+DROP TABLE IF EXISTS notas;
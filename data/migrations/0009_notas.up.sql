@@ -0,0 +1,35 @@
+-- Notas crédito y débito. Cada nota referencia una factura emitida, tiene sus
+-- propias líneas y su propio consecutivo por usuario y tipo de nota.
+CREATE TABLE IF NOT EXISTS notas (
+    id SERIAL PRIMARY KEY,
+    factura_id INTEGER NOT NULL REFERENCES facturas (id) ON DELETE CASCADE,
+    tipo TEXT NOT NULL CHECK (tipo IN ('credito', 'debito')),
+    consecutivo BIGINT NOT NULL,
+    numero TEXT NOT NULL,
+    codigo_concepto TEXT NOT NULL,
+    descripcion TEXT NOT NULL DEFAULT '',
+    fecha DATE NOT NULL,
+    servicios JSONB NOT NULL DEFAULT '[]',
+    impuestos JSONB NOT NULL DEFAULT '[]',
+    subtotal NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    total_iva NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    total NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    creado_en TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (usuario_id, tipo, consecutivo)
+);
+
+CREATE INDEX IF NOT EXISTS notas_factura_idx ON notas (factura_id);
+
+-- Último consecutivo asignado a cada tipo de nota de cada usuario
+CREATE TABLE IF NOT EXISTS consecutivos_notas (
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    tipo TEXT NOT NULL,
+    ultimo BIGINT NOT NULL,
+    PRIMARY KEY (usuario_id, tipo)
+);
+
+-- Totales de las notas de cada factura, para calcular su saldo sin sumar las notas en cada consulta
+ALTER TABLE facturas
+    ADD COLUMN IF NOT EXISTS total_notas_credito NUMERIC(18, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS total_notas_debito NUMERIC(18, 2) NOT NULL DEFAULT 0;
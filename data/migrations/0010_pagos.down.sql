@@ -0,0 +1,2 @@
+ALTER TABLE facturas DROP COLUMN IF EXISTS total_pagado;
+DROP TABLE IF EXISTS pagos;
@@ -0,0 +1,19 @@
+-- Libro de pagos. Los movimientos no se modifican: un pago se reversa con
+-- otro movimiento por el valor negativo que apunta al original en reversa_de.
+CREATE TABLE IF NOT EXISTS pagos (
+    id SERIAL PRIMARY KEY,
+    factura_id INTEGER NOT NULL REFERENCES facturas (id) ON DELETE CASCADE,
+    valor NUMERIC(18, 2) NOT NULL CHECK (valor <> 0),
+    fecha DATE NOT NULL,
+    metodo TEXT NOT NULL,
+    referencia TEXT NOT NULL DEFAULT '',
+    motivo TEXT NOT NULL DEFAULT '',
+    reversa_de INTEGER UNIQUE REFERENCES pagos (id),
+    usuario_id INTEGER REFERENCES usuarios (id) ON DELETE SET NULL,
+    creado_en TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS pagos_factura_idx ON pagos (factura_id);
+
+-- Suma de los pagos de cada factura, para calcular su saldo sin recorrer el libro en cada consulta
+ALTER TABLE facturas ADD COLUMN IF NOT EXISTS total_pagado NUMERIC(18, 2) NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE facturas DROP COLUMN IF EXISTS fecha_vencimiento;
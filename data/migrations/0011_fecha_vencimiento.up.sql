@@ -0,0 +1,3 @@
+-- Fecha de vencimiento opcional. El informe de cartera usa la fecha de la
+-- factura cuando no la tiene.
+ALTER TABLE facturas ADD COLUMN IF NOT EXISTS fecha_vencimiento DATE;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS facturas_empresa_idx;
+ALTER TABLE facturas
+    DROP COLUMN IF EXISTS empresa_id,
+    DROP COLUMN IF EXISTS direccion_empresa,
+    DROP COLUMN IF EXISTS ciudad_empresa,
+    DROP COLUMN IF EXISTS correo_empresa,
+    DROP COLUMN IF EXISTS telefono_empresa;
+DROP TABLE IF EXISTS empresas;
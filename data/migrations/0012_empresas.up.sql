@@ -0,0 +1,50 @@
+-- Empresas cliente de cada usuario. El NIT se guarda con su dígito de
+-- verificación cuando se conoce; la unicidad se controla sobre el número sin
+-- él, así que "900123456" y "900123456-7" son la misma empresa.
+CREATE TABLE IF NOT EXISTS empresas (
+    id SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    nombre TEXT NOT NULL,
+    nit TEXT NOT NULL,
+    direccion TEXT NOT NULL DEFAULT '',
+    ciudad TEXT NOT NULL DEFAULT '',
+    correo TEXT NOT NULL DEFAULT '',
+    telefono TEXT NOT NULL DEFAULT '',
+    creado_en TIMESTAMP NOT NULL DEFAULT NOW(),
+    actualizado_en TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS empresas_usuario_nit_idx ON empresas (usuario_id, split_part(nit, '-', 1));
+
+-- La factura referencia la empresa y conserva una copia de sus datos
+ALTER TABLE facturas
+    ADD COLUMN IF NOT EXISTS empresa_id INTEGER REFERENCES empresas (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS direccion_empresa TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ciudad_empresa TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS correo_empresa TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS telefono_empresa TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS facturas_empresa_idx ON facturas (empresa_id);
+
+-- Una empresa por usuario y NIT a partir de las facturas existentes. El nombre
+-- y el NIT son los de la factura más reciente de cada grupo; las facturas
+-- conservan los datos con que se hicieron.
+INSERT INTO empresas (usuario_id, nombre, nit)
+SELECT DISTINCT ON (usuario_id, nit_base) usuario_id, nombre, nit
+FROM (
+    SELECT usuario_id, id, fecha,
+        trim(nombre_empresa) AS nombre,
+        replace(replace(nit_empresa, '.', ''), ' ', '') AS nit,
+        split_part(replace(replace(nit_empresa, '.', ''), ' ', ''), '-', 1) AS nit_base
+    FROM facturas
+    WHERE trim(nit_empresa) <> ''
+) AS facturas_empresas
+ORDER BY usuario_id, nit_base, fecha DESC, id DESC
+ON CONFLICT DO NOTHING;
+
+UPDATE facturas
+SET empresa_id = empresas.id
+FROM empresas
+WHERE empresas.usuario_id = facturas.usuario_id
+    AND split_part(empresas.nit, '-', 1) = split_part(replace(replace(facturas.nit_empresa, '.', ''), ' ', ''), '-', 1)
+    AND facturas.empresa_id IS NULL;
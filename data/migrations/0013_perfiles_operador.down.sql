@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cuentas_bancarias;
+DROP TABLE IF EXISTS perfiles_operador;
@@ -0,0 +1,38 @@
+-- Perfil de operador de cada usuario y sus cuentas bancarias. A lo sumo una
+-- cuenta por usuario es la predeterminada.
+CREATE TABLE IF NOT EXISTS perfiles_operador (
+    usuario_id INTEGER PRIMARY KEY REFERENCES usuarios (id) ON DELETE CASCADE,
+    nombre TEXT NOT NULL DEFAULT '',
+    tipo_documento TEXT NOT NULL DEFAULT '',
+    documento TEXT NOT NULL DEFAULT '',
+    ciudad_expedicion_documento TEXT NOT NULL DEFAULT '',
+    celular TEXT NOT NULL DEFAULT '',
+    actualizado_en TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS cuentas_bancarias (
+    id SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    numero TEXT NOT NULL,
+    tipo TEXT NOT NULL DEFAULT '',
+    banco TEXT NOT NULL,
+    predeterminada BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE (usuario_id, numero)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cuentas_bancarias_predeterminada_idx ON cuentas_bancarias (usuario_id) WHERE predeterminada;
+
+-- Cada usuario arranca con los datos de operador de su factura más reciente
+INSERT INTO perfiles_operador (usuario_id, nombre, tipo_documento, documento, ciudad_expedicion_documento, celular)
+SELECT DISTINCT ON (usuario_id) usuario_id, nombre_operador, tipo_documento_operador, documento_operador,
+    ciudad_expedicion_documento_operador, celular_operador
+FROM facturas
+ORDER BY usuario_id, fecha DESC, id DESC
+ON CONFLICT DO NOTHING;
+
+INSERT INTO cuentas_bancarias (usuario_id, numero, tipo, banco, predeterminada)
+SELECT DISTINCT ON (usuario_id) usuario_id, numero_cuenta_bancaria_operador, tipo_cuenta_bancaria_operador, banco_operador, TRUE
+FROM facturas
+WHERE numero_cuenta_bancaria_operador <> ''
+ORDER BY usuario_id, fecha DESC, id DESC
+ON CONFLICT DO NOTHING;
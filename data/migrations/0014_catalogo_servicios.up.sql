@@ -0,0 +1,15 @@
+-- Catálogo de servicios de cada usuario. Las facturas copian los datos del
+-- servicio en sus líneas y guardan su id en catalogo_id, así que modificar o
+-- eliminar un servicio no cambia las facturas ya hechas.
+CREATE TABLE IF NOT EXISTS catalogo_servicios (
+    id SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    descripcion TEXT NOT NULL,
+    unidad TEXT NOT NULL DEFAULT '',
+    valor_unitario NUMERIC(18, 2) NOT NULL DEFAULT 0 CHECK (valor_unitario >= 0),
+    tarifa_iva TEXT NOT NULL DEFAULT 'exento',
+    creado_en TIMESTAMP NOT NULL DEFAULT NOW(),
+    actualizado_en TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS catalogo_servicios_usuario_descripcion_idx ON catalogo_servicios (usuario_id, lower(descripcion));
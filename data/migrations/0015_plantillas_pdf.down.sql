@@ -0,0 +1,2 @@
+ALTER TABLE usuarios DROP COLUMN IF EXISTS plantilla_pdf_id;
+DROP TABLE IF EXISTS plantillas_pdf;
@@ -0,0 +1,16 @@
+-- Plantillas de PDF administradas. A lo sumo una es la predeterminada; los
+-- usuarios sin plantilla asignada usan esa o, si no hay, el diseño incluido
+-- en la aplicación.
+CREATE TABLE IF NOT EXISTS plantillas_pdf (
+    id SERIAL PRIMARY KEY,
+    nombre TEXT NOT NULL UNIQUE,
+    descripcion TEXT NOT NULL DEFAULT '',
+    diseno JSONB NOT NULL,
+    predeterminada BOOLEAN NOT NULL DEFAULT FALSE,
+    creado_en TIMESTAMP NOT NULL DEFAULT NOW(),
+    actualizado_en TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS plantillas_pdf_predeterminada_idx ON plantillas_pdf (predeterminada) WHERE predeterminada;
+
+ALTER TABLE usuarios ADD COLUMN IF NOT EXISTS plantilla_pdf_id INTEGER REFERENCES plantillas_pdf (id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS facturas_actualizado_en ON facturas;
+DROP FUNCTION IF EXISTS facturas_marcar_actualizacion();
+ALTER TABLE facturas DROP COLUMN IF EXISTS actualizado_en;
@@ -0,0 +1,15 @@
+-- Fecha del último cambio de cada factura, para responder Last-Modified al
+-- descargar su PDF. La mantiene un trigger, así que cubre también los cambios
+-- que hacen las notas, los pagos y las transiciones de estado.
+ALTER TABLE facturas ADD COLUMN IF NOT EXISTS actualizado_en TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE OR REPLACE FUNCTION facturas_marcar_actualizacion() RETURNS trigger AS $$
+BEGIN
+    NEW.actualizado_en = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS facturas_actualizado_en ON facturas;
+CREATE TRIGGER facturas_actualizado_en BEFORE UPDATE ON facturas
+    FOR EACH ROW EXECUTE FUNCTION facturas_marcar_actualizacion();
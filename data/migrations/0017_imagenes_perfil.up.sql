@@ -0,0 +1,10 @@
+-- Logo y firma que el usuario sube a su perfil para imprimirlos en el PDF de
+-- sus facturas. Cada usuario tiene a lo sumo una imagen de cada tipo.
+CREATE TABLE IF NOT EXISTS imagenes_perfil (
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    tipo TEXT NOT NULL CHECK (tipo IN ('logo', 'firma')),
+    tipo_contenido TEXT NOT NULL,
+    contenido BYTEA NOT NULL,
+    actualizado_en TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (usuario_id, tipo)
+);
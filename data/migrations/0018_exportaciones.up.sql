@@ -0,0 +1,14 @@
+-- Exportaciones en segundo plano de los PDF de varias facturas. filtro guarda
+-- el filtro del listado con que se pidió y archivo la ruta del ZIP generado.
+CREATE TABLE IF NOT EXISTS exportaciones (
+    id SERIAL PRIMARY KEY,
+    usuario_id INTEGER NOT NULL REFERENCES usuarios (id) ON DELETE CASCADE,
+    filtro JSONB NOT NULL,
+    estado TEXT NOT NULL DEFAULT 'pendiente',
+    total INTEGER NOT NULL DEFAULT 0,
+    procesadas INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    archivo TEXT NOT NULL DEFAULT '',
+    creado_en TIMESTAMP NOT NULL DEFAULT NOW(),
+    terminado_en TIMESTAMP
+);
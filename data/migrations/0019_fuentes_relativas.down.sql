@@ -0,0 +1,18 @@
+-- Devuelve su ruta original solo a las fuentes que cambió la migración de
+-- subida y que no se han editado desde entonces.
+UPDATE plantillas_pdf AS plantillas
+SET diseno = jsonb_set(plantillas.diseno, '{fuentes}', (
+        SELECT COALESCE(jsonb_agg(
+            CASE WHEN anteriores.archivo IS NOT NULL
+                    AND fuentes.fuente->>'archivo' = regexp_replace(anteriores.archivo, '^(\./)?font/', '')
+                THEN jsonb_set(fuentes.fuente, '{archivo}', to_jsonb(anteriores.archivo))
+                ELSE fuentes.fuente
+            END ORDER BY fuentes.posicion), '[]'::jsonb)
+        FROM jsonb_array_elements(plantillas.diseno->'fuentes') WITH ORDINALITY AS fuentes (fuente, posicion)
+        LEFT JOIN plantillas_pdf_fuentes_anteriores AS anteriores
+            ON anteriores.plantilla_id = plantillas.id AND anteriores.posicion = fuentes.posicion)),
+    actualizado_en = NOW()
+WHERE plantillas.id IN (SELECT plantilla_id FROM plantillas_pdf_fuentes_anteriores)
+    AND jsonb_typeof(plantillas.diseno->'fuentes') = 'array';
+
+DROP TABLE IF EXISTS plantillas_pdf_fuentes_anteriores;
@@ -0,0 +1,29 @@
+-- Los archivos de fuente de las plantillas pasan a ser relativos al directorio
+-- de fuentes (FONTS_DIR): "./font/DejaVuSans.ttf" queda como "DejaVuSans.ttf".
+-- plantillas_pdf_fuentes_anteriores guarda la ruta original de cada fuente
+-- que se cambia, para que la migración de bajada revierta solo esas.
+CREATE TABLE IF NOT EXISTS plantillas_pdf_fuentes_anteriores (
+    plantilla_id INTEGER NOT NULL REFERENCES plantillas_pdf (id) ON DELETE CASCADE,
+    posicion INTEGER NOT NULL,
+    archivo TEXT NOT NULL,
+    PRIMARY KEY (plantilla_id, posicion)
+);
+
+INSERT INTO plantillas_pdf_fuentes_anteriores (plantilla_id, posicion, archivo)
+SELECT plantillas.id, fuentes.posicion, fuentes.fuente->>'archivo'
+FROM plantillas_pdf AS plantillas,
+    jsonb_array_elements(CASE WHEN jsonb_typeof(plantillas.diseno->'fuentes') = 'array' THEN plantillas.diseno->'fuentes' ELSE '[]'::jsonb END)
+        WITH ORDINALITY AS fuentes (fuente, posicion)
+WHERE fuentes.fuente->>'archivo' ~ '^(\./)?font/'
+ON CONFLICT DO NOTHING;
+
+UPDATE plantillas_pdf
+SET diseno = jsonb_set(diseno, '{fuentes}', (
+        SELECT COALESCE(jsonb_agg(
+            CASE WHEN fuente->>'archivo' ~ '^(\./)?font/'
+                THEN jsonb_set(fuente, '{archivo}', to_jsonb(regexp_replace(fuente->>'archivo', '^(\./)?font/', '')))
+                ELSE fuente
+            END ORDER BY posicion), '[]'::jsonb)
+        FROM jsonb_array_elements(diseno->'fuentes') WITH ORDINALITY AS fuentes (fuente, posicion))),
+    actualizado_en = NOW()
+WHERE id IN (SELECT plantilla_id FROM plantillas_pdf_fuentes_anteriores);
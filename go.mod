@@ -2,7 +2,10 @@ module facturaexpress
 
 go 1.20
 
-require github.com/gin-gonic/gin v1.9.1
+require (
+	github.com/boombuler/barcode v1.0.1
+	github.com/gin-gonic/gin v1.9.1
+)
 
 require (
 	github.com/chenzhuoyu/iasm v0.9.1 // indirect
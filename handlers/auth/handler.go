@@ -0,0 +1,15 @@
+package handlers
+
+import "facturaexpress/interfaces"
+
+// AuthHandler agrupa los controladores de registro, inicio y cierre de sesión.
+type AuthHandler struct {
+	users      interfaces.UserRepository
+	roles      interfaces.RoleRepository
+	jwtKey     []byte
+	expTimeStr string
+}
+
+func NewAuthHandler(users interfaces.UserRepository, roles interfaces.RoleRepository, jwtKey []byte, expTimeStr string) *AuthHandler {
+	return &AuthHandler{users: users, roles: roles, jwtKey: jwtKey, expTimeStr: expTimeStr}
+}
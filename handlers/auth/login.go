@@ -3,7 +3,6 @@ package handlers
 import (
 	"database/sql"
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/helpers"
 	"facturaexpress/models"
 	"log"
@@ -13,16 +12,14 @@ import (
 )
 
 // Login maneja el inicio de sesión del usuario y la generación de tokens.
-func Login(c *gin.Context, jwtKey []byte, expTimeStr string) {
+func (h *AuthHandler) Login(c *gin.Context) {
 	var loginData models.LoginData
 	if err := c.ShouldBindJSON(&loginData); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrBadRequest, "Error al leer los datos de inicio de sesión."))
 		return
 	}
 
-	db := data.GetInstance()
-
-	user, err := helpers.VerifyCredentials(db, loginData.Email, loginData.Password)
+	user, err := helpers.VerifyCredentials(h.users, loginData.Email, loginData.Password)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponseInit(common.ErrEmailNotFound, "No se encontró ningún usuario con el correo electrónico que ingresaste"))
@@ -32,20 +29,14 @@ func Login(c *gin.Context, jwtKey []byte, expTimeStr string) {
 		return
 	}
 
-	tokenString, err := helpers.GenerateJWTToken(jwtKey, user.ID, user.Role, expTimeStr)
+	tokenString, err := helpers.GenerateJWTToken(h.jwtKey, user.ID, user.Role, h.expTimeStr)
 	if err != nil {
 		log.Printf("%v", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrJWTGenerationError, "No se pudo generar el token JWT debido a un problema interno"))
 		return
 	}
 
-	stmt, err := db.Prepare(`UPDATE usuarios SET jwt_token = $1 WHERE id = $2`)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrJWTStorageError, "Ocurrió un problema al intentar almacenar el token JWT del usuario en la base de datos"))
-		return
-	}
-	defer stmt.Close()
-	if _, err = stmt.Exec(tokenString, user.ID); err != nil {
+	if err = h.users.SaveToken(user.ID, tokenString); err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrJWTStorageError, "Ocurrió un problema al intentar almacenar el token JWT del usuario en la base de datos"))
 		return
 	}
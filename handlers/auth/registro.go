@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/helpers"
 	"facturaexpress/models"
 	"net/http"
@@ -12,21 +11,19 @@ import (
 )
 
 // Register maneja el registro de usuarios.
-func Register(c *gin.Context) {
+func (h *AuthHandler) Register(c *gin.Context) {
 	var user models.User
 	if err := c.ShouldBindJSON(&user); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrJSONBindingFailed, "Error al procesar los datos del usuario."))
 		return
 	}
 
-	db := data.GetInstance()
-
-	if err := helpers.CheckUsernameEmail(db, user.Username, user.Email); err != nil {
+	if err := helpers.CheckUsernameEmail(h.users, user.Username, user.Email); err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
 
-	if err := helpers.CheckRoleExists(db, common.USER); err != nil {
+	if err := helpers.CheckRoleExists(h.roles, common.USER); err != nil {
 		c.JSON(http.StatusBadRequest, err)
 		return
 	}
@@ -37,13 +34,13 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	userID, err := helpers.SaveUser(db, user.Username, hashedPassword, user.Email)
+	userID, err := helpers.SaveUser(h.users, user.Username, hashedPassword, user.Email)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, err)
 		return
 	}
 
-	if err := helpers.SaveUserRole(db, userID); err != nil {
+	if err := helpers.SaveUserRole(h.roles, userID); err != nil {
 		c.JSON(http.StatusInternalServerError, err)
 		return
 	}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCatalogItem agrega un servicio al catálogo del usuario autenticado.
+func (h *CatalogHandler) CreateCatalogItem(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.JSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return
+	}
+
+	var item models.CatalogItem
+	if err := c.BindJSON(&item); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	if err := helpers.ValidateCatalogItem(&item, h.taxRates); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	item.ID = 0
+	item.UserID = claims.UserID
+
+	if !saveCatalogItem(c, func() error { return h.catalog.Create(&item) }) {
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Servicio creado correctamente", "item": item})
+}
+
+// saveCatalogItem ejecuta save y responde el error si lo hay: 409 si la
+// descripción ya está en el catálogo. Devuelve false si respondió un error.
+func saveCatalogItem(c *gin.Context, save func() error) bool {
+	err := save()
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		c.JSON(http.StatusConflict, errJSON)
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al guardar el servicio."))
+		return false
+	}
+	return true
+}
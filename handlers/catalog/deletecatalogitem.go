@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteCatalogItem elimina un servicio del catálogo. Las líneas de factura
+// que lo referencian conservan su copia de los datos.
+func (h *CatalogHandler) DeleteCatalogItem(c *gin.Context) {
+	item, ok := h.loadAuthorizedItem(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.catalog.Delete(item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al eliminar el servicio."))
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrCatalogItemNotFound, "No se encontró el servicio con el ID especificado."))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Servicio eliminado correctamente"})
+}
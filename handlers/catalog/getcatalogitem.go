@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCatalogItem devuelve un servicio del catálogo. Solo su dueño o un administrador pueden consultarlo.
+func (h *CatalogHandler) GetCatalogItem(c *gin.Context) {
+	item, ok := h.loadAuthorizedItem(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// loadAuthorizedItem obtiene el servicio indicado en el parámetro id y
+// verifica que el usuario autenticado sea su dueño o tenga el rol ADMIN. Los
+// servicios de otros usuarios se responden como inexistentes. Si algo falla
+// responde al cliente y devuelve false.
+func (h *CatalogHandler) loadAuthorizedItem(c *gin.Context) (models.CatalogItem, bool) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return models.CatalogItem{}, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidID, "El valor del parámetro id debe ser un número entero positivo"))
+		return models.CatalogItem{}, false
+	}
+
+	item, err := h.catalog.GetByID(id)
+	if err == sql.ErrNoRows || (err == nil && item.UserID != claims.UserID && claims.Role != common.ADMIN) {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrCatalogItemNotFound, "No se encontró el servicio con el ID especificado."))
+		return item, false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener el servicio."))
+		return item, false
+	}
+	return item, true
+}
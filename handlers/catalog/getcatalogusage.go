@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCatalogUsage devuelve los servicios del catálogo más facturados en las
+// facturas emitidas, enviadas o pagadas, con la cantidad y el valor
+// facturados. Acepta fecha_desde y fecha_hasta (AAAA-MM-DD), limit (10 por
+// defecto, máximo 100) y, para el administrador, usuario_id.
+func (h *CatalogHandler) GetCatalogUsage(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidLimitParam, "El parámetro 'limit' debe ser un número entre 1 y 100"))
+		return
+	}
+
+	filter := models.CatalogUsageFilter{
+		DateFrom: strings.TrimSpace(c.Query("fecha_desde")),
+		DateTo:   strings.TrimSpace(c.Query("fecha_hasta")),
+		Limit:    limit,
+	}
+	for _, date := range []struct{ param, value string }{{"fecha_desde", filter.DateFrom}, {"fecha_hasta", filter.DateTo}} {
+		if date.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date.value); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro '"+date.param+"' debe tener el formato AAAA-MM-DD."))
+			return
+		}
+	}
+	var ok bool
+	if filter.UserID, ok = catalogOwnerFilter(c, claims); !ok {
+		return
+	}
+
+	usage, err := h.catalog.Usage(filter)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al calcular el uso del catálogo"))
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
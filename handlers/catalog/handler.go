@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"facturaexpress/interfaces"
+	"facturaexpress/taxes"
+)
+
+// CatalogHandler agrupa los controladores del catálogo de servicios.
+type CatalogHandler struct {
+	catalog  interfaces.CatalogRepository
+	taxRates taxes.Rates
+}
+
+func NewCatalogHandler(catalog interfaces.CatalogRepository, taxRates taxes.Rates) *CatalogHandler {
+	return &CatalogHandler{catalog: catalog, taxRates: taxRates}
+}
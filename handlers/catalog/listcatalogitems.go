@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListCatalogItems lista el catálogo de servicios paginado. q busca en la
+// descripción, primero los servicios que empiezan por q. Los usuarios que no
+// son administradores solo ven su propio catálogo; el administrador ve todos
+// y puede filtrar por usuario_id.
+func (h *CatalogHandler) ListCatalogItems(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidPageParam, "El parámetro 'page' debe ser un número entero positivo"))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidLimitParam, "El parámetro 'limit' debe ser un número entero positivo"))
+		return
+	}
+	if limit > 100 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrLimitTooHigh, "El parámetro 'limit' no puede ser mayor a 100"))
+		return
+	}
+
+	filter := models.CatalogFilter{Query: strings.TrimSpace(c.Query("q")), Limit: limit, Offset: (page - 1) * limit}
+	var ok bool
+	if filter.UserID, ok = catalogOwnerFilter(c, claims); !ok {
+		return
+	}
+
+	items, err := h.catalog.List(filter)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener el catálogo"))
+		return
+	}
+	total, err := h.catalog.Count(filter)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al contar los servicios del catálogo"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"items":       items,
+		"total_pages": int(math.Ceil(float64(total) / float64(limit))),
+		"page":        page,
+	})
+}
+
+// catalogOwnerFilter devuelve el dueño cuyo catálogo se consulta: el usuario
+// autenticado o, para el administrador, el usuario_id indicado (0 si no lo
+// indica). Si el parámetro es inválido responde al cliente y devuelve false.
+func catalogOwnerFilter(c *gin.Context, claims *models.Claims) (int64, bool) {
+	if claims.Role != common.ADMIN {
+		return claims.UserID, true
+	}
+	userIDStr := c.Query("usuario_id")
+	if userIDStr == "" {
+		return 0, true
+	}
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil || userID <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidUserID, "El parámetro 'usuario_id' debe ser un número entero positivo."))
+		return 0, false
+	}
+	return userID, true
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateCatalogItem modifica un servicio del catálogo. Las facturas ya hechas
+// conservan los datos con que se hicieron.
+func (h *CatalogHandler) UpdateCatalogItem(c *gin.Context) {
+	current, ok := h.loadAuthorizedItem(c)
+	if !ok {
+		return
+	}
+
+	var item models.CatalogItem
+	if err := c.BindJSON(&item); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	if err := helpers.ValidateCatalogItem(&item, h.taxRates); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	item.ID = current.ID
+	item.UserID = current.UserID
+
+	updated := false
+	if !saveCatalogItem(c, func() (err error) { updated, err = h.catalog.Update(item); return err }) {
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrCatalogItemNotFound, "No se encontró el servicio con el ID especificado."))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Servicio actualizado correctamente", "item": item})
+}
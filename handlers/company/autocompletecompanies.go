@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAutocompleteResults limita las sugerencias que devuelve el autocompletado.
+const maxAutocompleteResults = 20
+
+// companySuggestion es lo que el autocompletado necesita mostrar de cada empresa.
+type companySuggestion struct {
+	ID   int64  `json:"id"`
+	Name string `json:"nombre"`
+	TIN  string `json:"nit"`
+}
+
+// AutocompleteCompanies sugiere empresas del directorio del usuario
+// autenticado cuyo nombre o NIT contengan q, primero las que empiezan por q.
+func (h *CompanyHandler) AutocompleteCompanies(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > maxAutocompleteResults {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidLimitParam, "El parámetro 'limit' debe ser un número entre 1 y "+strconv.Itoa(maxAutocompleteResults)))
+		return
+	}
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusOK, []companySuggestion{})
+		return
+	}
+
+	companies, err := h.companies.List(models.CompanyFilter{UserID: claims.UserID, Query: query, Limit: limit})
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al buscar las empresas"))
+		return
+	}
+
+	suggestions := make([]companySuggestion, 0, len(companies))
+	for _, company := range companies {
+		suggestions = append(suggestions, companySuggestion{ID: company.ID, Name: company.Name, TIN: company.TIN})
+	}
+	c.JSON(http.StatusOK, suggestions)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCompany registra una empresa en el directorio del usuario autenticado.
+func (h *CompanyHandler) CreateCompany(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.JSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return
+	}
+
+	var company models.Company
+	if err := c.BindJSON(&company); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	if err := helpers.ValidateCompany(&company, ""); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	company.ID = 0
+	company.UserID = claims.UserID
+
+	if !saveCompany(c, func() error { return h.companies.Create(&company) }) {
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Empresa creada correctamente", "company": company})
+}
+
+// saveCompany ejecuta save y responde el error si lo hay: 409 si el NIT ya
+// está registrado. Devuelve false si respondió un error.
+func saveCompany(c *gin.Context, save func() error) bool {
+	err := save()
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		c.JSON(http.StatusConflict, errJSON)
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al guardar la empresa."))
+		return false
+	}
+	return true
+}
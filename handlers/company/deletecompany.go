@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteCompany elimina una empresa del directorio. Las facturas que la
+// referencian conservan su copia de los datos de la empresa.
+func (h *CompanyHandler) DeleteCompany(c *gin.Context) {
+	company, ok := h.loadAuthorizedCompany(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.companies.Delete(company.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al eliminar la empresa."))
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrCompanyNotFound, "No se encontró la empresa con el ID especificado."))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Empresa eliminada correctamente"})
+}
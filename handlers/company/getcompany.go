@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCompany devuelve una empresa. Solo su dueño o un administrador pueden consultarla.
+func (h *CompanyHandler) GetCompany(c *gin.Context) {
+	company, ok := h.loadAuthorizedCompany(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, company)
+}
+
+// loadAuthorizedCompany obtiene la empresa indicada en el parámetro id y
+// verifica que el usuario autenticado sea su dueño o tenga el rol ADMIN. Las
+// empresas de otros usuarios se responden como inexistentes. Si algo falla
+// responde al cliente y devuelve false.
+func (h *CompanyHandler) loadAuthorizedCompany(c *gin.Context) (models.Company, bool) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return models.Company{}, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidID, "El valor del parámetro id debe ser un número entero positivo"))
+		return models.Company{}, false
+	}
+
+	company, err := h.companies.GetByID(id)
+	if err == sql.ErrNoRows || (err == nil && company.UserID != claims.UserID && claims.Role != common.ADMIN) {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrCompanyNotFound, "No se encontró la empresa con el ID especificado."))
+		return company, false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la empresa."))
+		return company, false
+	}
+	return company, true
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import "facturaexpress/interfaces"
+
+// CompanyHandler agrupa los controladores del directorio de empresas cliente.
+type CompanyHandler struct {
+	companies interfaces.CompanyRepository
+}
+
+func NewCompanyHandler(companies interfaces.CompanyRepository) *CompanyHandler {
+	return &CompanyHandler{companies: companies}
+}
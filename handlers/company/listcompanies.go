@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListCompanies lista el directorio de empresas paginado. q busca en el
+// nombre y el NIT. Los usuarios que no son administradores solo ven sus
+// propias empresas; el administrador ve todas y puede filtrar por usuario_id.
+func (h *CompanyHandler) ListCompanies(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidPageParam, "El parámetro 'page' debe ser un número entero positivo"))
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidLimitParam, "El parámetro 'limit' debe ser un número entero positivo"))
+		return
+	}
+	if limit > 100 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrLimitTooHigh, "El parámetro 'limit' no puede ser mayor a 100"))
+		return
+	}
+
+	filter := models.CompanyFilter{Query: strings.TrimSpace(c.Query("q")), Limit: limit, Offset: (page - 1) * limit}
+	if claims.Role != common.ADMIN {
+		filter.UserID = claims.UserID
+	} else if userIDStr := c.Query("usuario_id"); userIDStr != "" {
+		filter.UserID, err = strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil || filter.UserID <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidUserID, "El parámetro 'usuario_id' debe ser un número entero positivo."))
+			return
+		}
+	}
+
+	companies, err := h.companies.List(filter)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener las empresas"))
+		return
+	}
+	total, err := h.companies.Count(filter)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al contar las empresas"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"companies":   companies,
+		"total_pages": int(math.Ceil(float64(total) / float64(limit))),
+		"page":        page,
+	})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateCompany modifica una empresa. Las facturas ya hechas conservan los
+// datos con que se hicieron.
+func (h *CompanyHandler) UpdateCompany(c *gin.Context) {
+	current, ok := h.loadAuthorizedCompany(c)
+	if !ok {
+		return
+	}
+
+	var company models.Company
+	if err := c.BindJSON(&company); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	if err := helpers.ValidateCompany(&company, ""); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	company.ID = current.ID
+	company.UserID = current.UserID
+
+	updated := false
+	if !saveCompany(c, func() (err error) { updated, err = h.companies.Update(company); return err }) {
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrCompanyNotFound, "No se encontró la empresa con el ID especificado."))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Empresa actualizada correctamente", "company": company})
+}
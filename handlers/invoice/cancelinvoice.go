@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"facturaexpress/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancelInvoice anula una factura emitida o enviada. El motivo es obligatorio.
+func (h *InvoiceHandler) CancelInvoice(c *gin.Context) {
+	invoice, ok := h.loadAuthorizedInvoice(c, "No tienes permiso para anular esta factura.")
+	if !ok {
+		return
+	}
+	event, ok := bindStatusChange(c, invoice, models.InvoiceStatusCancelled, true)
+	if !ok {
+		return
+	}
+
+	cancelled, err := h.invoices.ChangeStatus(int64(invoice.ID), event)
+	respondStatusChange(c, cancelled, err, "Factura anulada correctamente", nil)
+}
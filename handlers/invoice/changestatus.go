@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindStatusChange lee el motivo del cambio de estado. El cuerpo es opcional
+// salvo que reasonRequired sea true. Si algo falla responde al cliente y devuelve false.
+func bindStatusChange(c *gin.Context, invoice models.Invoice, toStatus string, reasonRequired bool) (models.InvoiceEvent, bool) {
+	var request models.StatusChangeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+			return models.InvoiceEvent{}, false
+		}
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+	if reasonRequired && request.Reason == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrMissingFields, "Debes indicar el motivo en el campo 'motivo'."))
+		return models.InvoiceEvent{}, false
+	}
+
+	claims := c.MustGet("claims").(*models.Claims)
+	return models.InvoiceEvent{
+		InvoiceID: int64(invoice.ID),
+		ToStatus:  toStatus,
+		Reason:    request.Reason,
+		UserID:    claims.UserID,
+	}, true
+}
+
+// respondStatusChange responde el resultado de Issue o ChangeStatus.
+func respondStatusChange(c *gin.Context, invoice models.Invoice, err error, message string, extra gin.H) {
+	if err == sql.ErrNoRows {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrInvoiceNotFound, "No se encontró la factura con el ID especificado."))
+		return
+	}
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		status := http.StatusUnprocessableEntity
+		if errJSON.Title == common.ErrInvalidStatusTransition {
+			status = http.StatusConflict
+		}
+		c.AbortWithStatusJSON(status, errJSON)
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al cambiar el estado de la factura."))
+		return
+	}
+
+	response := gin.H{"message": message, "estado": invoice.Status, "invoice": invoice}
+	for key, value := range extra {
+		response[key] = value
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// requireDraft comprueba que la factura exista y siga en borrador, que es el
+// único estado en que se puede modificar o eliminar. Si no, responde al
+// cliente y devuelve false.
+func (h *InvoiceHandler) requireDraft(c *gin.Context, id int64, action string) bool {
+	status, err := h.invoices.GetStatus(id)
+	if err == sql.ErrNoRows {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrInvoiceNotFound, "No se encontró la factura con el ID especificado"))
+		return false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener el estado de la factura."))
+		return false
+	}
+	if status != models.InvoiceStatusDraft {
+		c.AbortWithStatusJSON(http.StatusConflict, models.ErrorResponseInit(common.ErrInvoiceNotEditable, "La factura está "+status+"; solo se puede "+action+" una factura en borrador."))
+		return false
+	}
+	return true
+}
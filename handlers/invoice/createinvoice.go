@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"encoding/json"
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/helpers"
 	"facturaexpress/models"
 	"net/http"
@@ -11,7 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func CreateInvoice(c *gin.Context) {
+func (h *InvoiceHandler) CreateInvoice(c *gin.Context) {
 	// Obtener el rol y el ID de usuario del token JWT
 	claims := c.MustGet("claims").(*models.Claims)
 	role := claims.Role
@@ -28,40 +26,65 @@ func CreateInvoice(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
 		return
 	}
+	// El número, el estado, los pagos y el saldo los asigna el servidor
+	invoice.ClearServerFields()
 
-	servicesJSON, err := json.Marshal(invoice.Services)
+	// Los datos del operador que no vengan en la factura se toman del perfil guardado
+	profile, err := h.profiles.GetByUserID(userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrServicesMarshalError, "Error al codificar los servicios en formato JSON."))
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener el perfil de operador."))
 		return
 	}
+	profile.FillOperator(&invoice.Operator)
 
-	db := data.GetInstance()
+	// La empresa se toma del directorio de empresas del usuario
+	if !h.resolveCompany(c, &invoice, userID) {
+		return
+	}
 
-	query := `INSERT INTO facturas (nombre_empresa, nit_empresa, fecha, servicios, valor_total, nombre_operador, tipo_documento_operador, documento_operador, ciudad_expedicion_documento_operador, celular_operador, numero_cuenta_bancaria_operador, tipo_cuenta_bancaria_operador, banco_operador, usuario_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13,$14) RETURNING id`
-	err = db.QueryRow(query,
-		invoice.Company.Name,
-		invoice.Company.TIN,
-		invoice.Date,
-		servicesJSON,
-		invoice.TotalValue,
-		invoice.Operator.Name,
-		invoice.Operator.DocumentType,
-		invoice.Operator.Document,
-		invoice.Operator.DocumentIssuanceCity,
-		invoice.Operator.Cellphone,
-		invoice.Operator.BankAccountNumber,
-		invoice.Operator.BankAccountType,
-		invoice.Operator.Bank,
-		userID).Scan(&invoice.ID)
+	// Las líneas que referencian el catálogo toman de él los datos que no traigan
+	if !h.applyCatalog(c, &invoice, userID) {
+		return
+	}
 
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al procesar las facturas"))
-		c.Abort()
+	// Validar y normalizar el NIT de la empresa y el documento del operador
+	if err := helpers.ValidateInvoiceParties(&invoice); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	if err := helpers.ValidateDueDate(&invoice); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	// Los subtotales y el valor total los calcula el servidor
+	if err := helpers.CalculateInvoiceTotals(&invoice, h.taxRates); err != nil {
+		c.JSON(calculationErrorStatus(err), err)
 		return
 	}
 
-	// Actualiza el objeto de factura con el ID de usuario correcto
+	// La factura siempre queda a nombre del usuario autenticado
 	invoice.UserID = userID
 
+	// La factura se crea como borrador; el número se asigna al emitirla. Una
+	// empresa nueva se registra en el directorio en la misma transacción
+	if err := h.invoices.Create(&invoice); err != nil {
+		if respondCompanyConflict(c, err) {
+			return
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al procesar las facturas"))
+		c.Abort()
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{"message": "Factura creada correctamente", "invoice": invoice})
 }
+
+// calculationErrorStatus elige el código HTTP para un error de CalculateInvoiceTotals:
+// 422 si el total enviado no cuadra con los servicios y 400 para datos inválidos.
+func calculationErrorStatus(err error) int {
+	if errJSON, ok := err.(*models.ErrorJson); ok && errJSON.Title == common.ErrTotalMismatch {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
@@ -1,9 +1,7 @@
 package handlers
 
 import (
-	"database/sql"
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/helpers"
 	"facturaexpress/models"
 	"net/http"
@@ -12,7 +10,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func DeleteInvoice(c *gin.Context) {
+func (h *InvoiceHandler) DeleteInvoice(c *gin.Context) {
 	// Get the role and user ID from the JWT token
 	claims := c.MustGet("claims").(*models.Claims)
 	role := claims.Role
@@ -40,29 +38,29 @@ func DeleteInvoice(c *gin.Context) {
 		return
 	}
 
-	var query string
+	// El administrador puede eliminar cualquier factura; los demás solo las propias
+	ownerID := userID
 	if role == common.ADMIN {
-		query = `DELETE FROM facturas WHERE id = $1`
-	} else {
-		query = `DELETE FROM facturas WHERE id = $1 AND usuario_id = $2`
+		ownerID = 0
+	} else if invoiceOwnerID, err := h.invoices.GetOwnerID(int64(id)); err == nil && invoiceOwnerID != userID {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrInvoiceNotFound, "No se encontró la factura con el ID especificado o no tienes permiso para eliminarla"))
+		c.Abort()
+		return
 	}
 
-	var result sql.Result
-
-	db := data.GetInstance()
-
-	if role == common.ADMIN {
-		result, err = db.Exec(query, id)
-	} else {
-		result, err = db.Exec(query, id, userID)
+	// Solo los borradores se pueden eliminar; una factura emitida se anula
+	if !h.requireDraft(c, int64(id), "eliminar") {
+		return
 	}
+
+	deleted, err := h.invoices.Delete(int64(id), ownerID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrQueryFailed, "Error al ejecutar la consulta SQL"))
 		c.Abort()
 		return
 	}
 
-	if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
+	if deleted {
 		c.JSON(http.StatusOK, gin.H{"message": "Factura eliminada correctamente"})
 	} else {
 		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrInvoiceNotFound, "No se encontró la factura con el ID especificado o no tienes permiso para eliminarla"))
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DownloadExport descarga el ZIP de una exportación terminada.
+func (h *InvoiceHandler) DownloadExport(c *gin.Context) {
+	export, ok := h.loadAuthorizedExport(c)
+	if !ok {
+		return
+	}
+	if export.Status != models.ExportStatusDone {
+		c.JSON(http.StatusConflict, models.ErrorResponseInit(common.ErrExportNotReady, "La exportación está "+export.Status+"; consulta su estado en "+newExportResponse(export).StatusURL+"."))
+		return
+	}
+	if _, err := os.Stat(export.File); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrExportNotFound, "El archivo de la exportación ya no está disponible. Vuelve a exportar las facturas."))
+		return
+	}
+	c.FileAttachment(export.File, fmt.Sprintf("facturas-%d.zip", export.ID))
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportResponse agrega a la exportación las rutas para consultarla y, cuando
+// termina, para descargarla.
+type exportResponse struct {
+	models.InvoiceExport
+	StatusURL   string `json:"url_estado"`
+	DownloadURL string `json:"url_descarga,omitempty"`
+}
+
+func newExportResponse(export models.InvoiceExport) exportResponse {
+	response := exportResponse{InvoiceExport: export, StatusURL: fmt.Sprintf("/v1/exports/%d", export.ID)}
+	if export.Status == models.ExportStatusDone {
+		response.DownloadURL = response.StatusURL + "/download"
+	}
+	return response
+}
+
+// ExportInvoices exporta en un ZIP los PDF de las facturas que cumplen los
+// mismos filtros del listado, con un indice.csv. Hasta exportSyncLimit
+// facturas el ZIP se devuelve en la misma respuesta, salvo que se pida
+// segundo_plano=true; las exportaciones más grandes responden 202 con la ruta
+// para consultar su avance.
+func (h *InvoiceHandler) ExportInvoices(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return
+	}
+
+	filter, err := helpers.ParseInvoiceFilter(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, err)
+		return
+	}
+	// Los usuarios que no son administradores solo exportan sus propias facturas, igual que en el listado
+	if claims.Role != common.ADMIN {
+		filter.UserID = claims.UserID
+	}
+
+	total, err := h.invoices.Count(filter)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al contar las facturas"))
+		return
+	}
+	if total == 0 {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrNotFound, "Ninguna factura cumple los filtros indicados."))
+		return
+	}
+	helpers.RemoveExpiredExports(h.exports)
+
+	if total <= exportSyncLimit && c.Query("segundo_plano") != "true" {
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", `attachment; filename="facturas.zip"`)
+		c.Status(http.StatusOK)
+		if _, err := h.writeInvoicesZIP(c.Writer, filter, nil); err != nil {
+			// Si ya se envió parte del ZIP el cliente lo recibe incompleto y solo queda el registro
+			log.Printf("exportación de facturas del usuario %d: %v", claims.UserID, err)
+			if !c.Writer.Written() {
+				c.Writer.Header().Del("Content-Type")
+				c.Writer.Header().Del("Content-Disposition")
+				c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al generar la exportación."))
+			}
+		}
+		return
+	}
+
+	export := models.InvoiceExport{UserID: claims.UserID, Filter: filter, Status: models.ExportStatusPending, Total: total}
+	if err := h.exports.Create(&export); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDatabaseSaveFailed, "Error al crear la exportación."))
+		return
+	}
+	go h.runExport(export)
+
+	response := newExportResponse(export)
+	c.Header("Location", response.StatusURL)
+	c.JSON(http.StatusAccepted, gin.H{"message": "La exportación se está generando", "exportacion": response})
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"facturaexpress/models"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+)
+
+const (
+	// exportSyncLimit es el máximo de facturas que se exportan en la misma
+	// petición; las exportaciones más grandes corren en segundo plano.
+	exportSyncLimit = 20
+	// maxConcurrentExports es el máximo de exportaciones en segundo plano que
+	// corren a la vez; las demás esperan como pendientes.
+	maxConcurrentExports = 2
+	// exportPageSize es el número de facturas que se leen por consulta.
+	exportPageSize = 100
+	// exportProgressStep es cada cuántas facturas se guarda el avance.
+	exportProgressStep = 10
+)
+
+// runExport genera en segundo plano el ZIP de una exportación y guarda el
+// resultado. Un error, o un pánico al dibujar una factura, deja la
+// exportación como fallida.
+func (h *InvoiceHandler) runExport(export models.InvoiceExport) {
+	h.exportSlots <- struct{}{}
+	defer func() { <-h.exportSlots }()
+
+	var file *os.File
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			h.finishExport(&export, file, fmt.Errorf("%v", recovered))
+		}
+	}()
+
+	if err := h.exports.Start(export.ID); err != nil {
+		log.Printf("exportación %d: %v", export.ID, err)
+	}
+	file, err := os.CreateTemp(h.exportDir, fmt.Sprintf("exportacion-%d-*.zip", export.ID))
+	if err != nil {
+		h.finishExport(&export, nil, err)
+		return
+	}
+	export.Processed, err = h.writeInvoicesZIP(file, export.Filter, func(processed int) {
+		if processed%exportProgressStep == 0 {
+			if err := h.exports.SetProgress(export.ID, processed); err != nil {
+				log.Printf("exportación %d: %v", export.ID, err)
+			}
+		}
+	})
+	h.finishExport(&export, file, err)
+}
+
+// finishExport cierra el archivo y guarda el estado final de la exportación.
+// Si hubo un error el archivo incompleto se elimina.
+func (h *InvoiceHandler) finishExport(export *models.InvoiceExport, file *os.File, err error) {
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Printf("exportación %d: %v", export.ID, err)
+		export.Status = models.ExportStatusFailed
+		export.Error = "No se pudo generar la exportación."
+		if file != nil {
+			os.Remove(file.Name())
+		}
+	} else {
+		export.Status = models.ExportStatusDone
+		export.File = file.Name()
+	}
+	if err := h.exports.Finish(export); err != nil {
+		log.Printf("exportación %d: %v", export.ID, err)
+	}
+}
+
+// writeInvoicesZIP escribe en w un ZIP con el PDF de cada factura que cumple
+// el filtro y un indice.csv con una fila por factura. Las facturas se leen por
+// páginas en el orden del filtro. progress, si no es nil, recibe el número de
+// facturas procesadas. Devuelve cuántas facturas quedaron en el archivo.
+func (h *InvoiceHandler) writeInvoicesZIP(w io.Writer, filter models.InvoiceFilter, progress func(processed int)) (int, error) {
+	archive := zip.NewWriter(w)
+	var index bytes.Buffer
+	indexWriter := csv.NewWriter(&index)
+	indexWriter.Write([]string{"archivo", "id", "numero", "fecha", "estado", "nit_empresa", "nombre_empresa", "valor_total", "valor_neto", "saldo", "estado_pago"})
+
+	processed := 0
+	filter.Limit = exportPageSize
+	for filter.Offset = 0; ; filter.Offset += exportPageSize {
+		invoices, err := h.invoices.List(filter)
+		if err != nil {
+			return processed, err
+		}
+		for _, invoice := range invoices {
+			p, err := h.prepareInvoicePDF(invoice)
+			if err != nil {
+				return processed, err
+			}
+			pdf, err := h.renderInvoicePDF(p)
+			if err != nil {
+				return processed, fmt.Errorf("factura %d: %w", invoice.ID, err)
+			}
+
+			// Los PDF ya vienen comprimidos, así que se guardan tal cual
+			name := fmt.Sprintf("factura-%d.pdf", invoice.ID)
+			entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: p.lastModified})
+			if err != nil {
+				return processed, err
+			}
+			if _, err := entry.Write(pdf); err != nil {
+				return processed, err
+			}
+			indexWriter.Write(exportIndexRecord(name, invoice))
+
+			processed++
+			if progress != nil {
+				progress(processed)
+			}
+		}
+		if len(invoices) < exportPageSize {
+			break
+		}
+	}
+
+	indexWriter.Flush()
+	entry, err := archive.Create("indice.csv")
+	if err != nil {
+		return processed, err
+	}
+	if _, err := entry.Write(index.Bytes()); err != nil {
+		return processed, err
+	}
+	return processed, archive.Close()
+}
+
+func exportIndexRecord(name string, invoice models.Invoice) []string {
+	date := invoice.Date
+	if len(date) > len("2006-01-02") {
+		date = date[:len("2006-01-02")]
+	}
+	return []string{
+		name,
+		strconv.Itoa(invoice.ID),
+		invoice.Number,
+		date,
+		invoice.Status,
+		invoice.Company.TIN,
+		invoice.Company.Name,
+		invoice.TotalValue.String(),
+		invoice.NetPayable.String(),
+		invoice.Balance.String(),
+		invoice.PaymentStatus,
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetExport devuelve el estado y el avance de una exportación y, cuando
+// termina, la ruta para descargarla.
+func (h *InvoiceHandler) GetExport(c *gin.Context) {
+	export, ok := h.loadAuthorizedExport(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, newExportResponse(export))
+}
+
+// loadAuthorizedExport obtiene la exportación indicada en el parámetro id y
+// verifica que el usuario autenticado la haya pedido o tenga el rol ADMIN.
+// Las exportaciones de otros usuarios se responden como inexistentes. Si algo
+// falla responde al cliente y devuelve false.
+func (h *InvoiceHandler) loadAuthorizedExport(c *gin.Context) (models.InvoiceExport, bool) {
+	claims := c.MustGet("claims").(*models.Claims)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidID, "El valor del parámetro id debe ser un número entero positivo"))
+		return models.InvoiceExport{}, false
+	}
+
+	export, err := h.exports.GetByID(id)
+	if err == sql.ErrNoRows || (err == nil && export.UserID != claims.UserID && claims.Role != common.ADMIN) {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrExportNotFound, "No se encontró la exportación con el ID especificado."))
+		return export, false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la exportación."))
+		return export, false
+	}
+	return export, true
+}
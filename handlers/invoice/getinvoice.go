@@ -1,44 +1,43 @@
 package handlers
 
 import (
-	"database/sql"
 	"encoding/json"
-	"facturaexpress/data"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
 	"facturaexpress/models"
-	"fmt"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func GetInvoice(c *gin.Context) (models.Invoice, error) {
-	// Get the invoice ID from the URL parameter
-	id := c.Param("id")
-
-	// Query the database to get the invoice information
-	query := `SELECT * FROM facturas WHERE id = $1`
-	db := data.GetInstance()
-	row := db.QueryRow(query, id)
-
-	// Decode the row into an Invoice struct
-	var invoice models.Invoice
-	var servicesJSON []byte
-	err := row.Scan(&invoice.ID, &invoice.Company.Name, &invoice.Company.TIN, &invoice.Date, &servicesJSON, &invoice.TotalValue, &invoice.Operator.Name, &invoice.Operator.DocumentType, &invoice.Operator.Document, &invoice.Operator.DocumentIssuanceCity, &invoice.Operator.Cellphone, &invoice.Operator.BankAccountNumber, &invoice.Operator.BankAccountType, &invoice.Operator.Bank, &invoice.UserID)
+// GetInvoice devuelve una factura en JSON. Solo su dueño o un administrador pueden consultarla.
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	invoice, ok := h.loadAuthorizedInvoice(c, "No tienes permiso para consultar esta factura.")
+	if !ok {
+		return
+	}
+	invoice.Date = helpers.FormatDateInSpanish(invoice.Date)
 
+	body, err := json.Marshal(invoice)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			//lint:ignore ST1005 Reason for ignoring warning
-			return invoice, fmt.Errorf("No se encontró la factura con el ID especificado.")
-
-		} else {
-			return invoice, err
-		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrServicesMarshalError, "Error al codificar la factura en formato JSON."))
+		return
 	}
 
-	// Decode the JSON data of the services into a slice of Service structs
-	err = json.Unmarshal(servicesJSON, &invoice.Services)
-	if err != nil {
-		return invoice, err
+	// El ETag permite al cliente repetir la consulta con If-None-Match y recibir 304 si nada cambió
+	etag := helpers.ComputeETag(body)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if helpers.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
 	}
 
-	return invoice, nil
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// loadAuthorizedInvoice obtiene la factura indicada en el parámetro id si el
+// usuario autenticado es su dueño o administrador; si no, responde al cliente y devuelve false.
+func (h *InvoiceHandler) loadAuthorizedInvoice(c *gin.Context, forbiddenMessage string) (models.Invoice, bool) {
+	return helpers.LoadAuthorizedInvoice(c, h.invoices, forbiddenMessage)
 }
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"facturaexpress/ubl"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetInvoiceXML devuelve la factura como documento UBL 2.1 con las extensiones
+// de la DIAN. Aplica la misma autorización que GeneratePDF.
+func (h *InvoiceHandler) GetInvoiceXML(c *gin.Context) {
+	invoice, ok := h.loadAuthorizedInvoice(c, "No tienes permiso para descargar el XML de esta factura.")
+	if !ok {
+		return
+	}
+
+	// La resolución que se informa es la del emisor de la factura
+	var numbering *models.Numbering
+	if current, err := h.numberings.GetByUserID(invoice.UserID); err == nil {
+		numbering = &current
+	}
+
+	doc, err := ubl.NewInvoice(invoice, numbering, h.dian)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrXMLGenerationFailed, "Error al generar el XML de la factura."))
+		return
+	}
+	body, err := ubl.Marshal(doc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrXMLGenerationFailed, "Error al generar el XML de la factura."))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="factura-%s.xml"`, doc.ID))
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"facturaexpress/interfaces"
+	"facturaexpress/pdftemplate"
+	"facturaexpress/taxes"
+	"facturaexpress/ubl"
+)
+
+// InvoiceHandler agrupa los controladores de facturas.
+type InvoiceHandler struct {
+	invoices    interfaces.InvoiceRepository
+	companies   interfaces.CompanyRepository
+	catalog     interfaces.CatalogRepository
+	users       interfaces.UserRepository
+	profiles    interfaces.ProfileRepository
+	numberings  interfaces.NumberingRepository
+	templates   interfaces.PDFTemplateRepository
+	pdfCache    *pdftemplate.Cache
+	fontDir     string
+	exports     interfaces.ExportRepository
+	exportDir   string
+	exportSlots chan struct{}
+	taxRates    taxes.Rates
+	dian        ubl.Settings
+}
+
+// Dependencies agrupa los servicios que usa InvoiceHandler. Los campos tienen
+// nombre para que no se puedan intercambiar por error al construirlo.
+type Dependencies struct {
+	Invoices   interfaces.InvoiceRepository
+	Companies  interfaces.CompanyRepository
+	Catalog    interfaces.CatalogRepository
+	Users      interfaces.UserRepository
+	Profiles   interfaces.ProfileRepository
+	Numberings interfaces.NumberingRepository
+	Templates  interfaces.PDFTemplateRepository
+	PDFCache   *pdftemplate.Cache
+	FontDir    string
+	Exports    interfaces.ExportRepository
+	ExportDir  string
+	TaxRates   taxes.Rates
+	DIAN       ubl.Settings
+}
+
+func NewInvoiceHandler(deps Dependencies) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoices:    deps.Invoices,
+		companies:   deps.Companies,
+		catalog:     deps.Catalog,
+		users:       deps.Users,
+		profiles:    deps.Profiles,
+		numberings:  deps.Numberings,
+		templates:   deps.Templates,
+		pdfCache:    deps.PDFCache,
+		fontDir:     deps.FontDir,
+		exports:     deps.Exports,
+		exportDir:   deps.ExportDir,
+		exportSlots: make(chan struct{}, maxConcurrentExports),
+		taxRates:    deps.TaxRates,
+		dian:        deps.DIAN,
+	}
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// applyCatalog copia en las líneas de la factura los datos de los servicios
+// del catálogo del dueño que referencian. Si algo falla responde al cliente y
+// devuelve false.
+func (h *InvoiceHandler) applyCatalog(c *gin.Context, invoice *models.Invoice, ownerID int64) bool {
+	err := helpers.ApplyCatalogItems(invoice.Services, h.catalog, ownerID)
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, errJSON)
+		return false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener los servicios del catálogo."))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resolveCompany toma la empresa de la factura del directorio del dueño. Si
+// algo falla responde al cliente y devuelve false.
+func (h *InvoiceHandler) resolveCompany(c *gin.Context, invoice *models.Invoice, ownerID int64) bool {
+	err := helpers.ResolveInvoiceCompany(invoice, h.companies, ownerID)
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		status := http.StatusBadRequest
+		if errJSON.Title == common.ErrCompanyNotFound {
+			status = http.StatusNotFound
+		}
+		c.AbortWithStatusJSON(status, errJSON)
+		return false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la empresa de la factura."))
+		return false
+	}
+	return true
+}
+
+// respondCompanyConflict responde 409 si el repositorio no pudo registrar la
+// empresa de la factura porque entre tanto se registró otra con el mismo NIT.
+func respondCompanyConflict(c *gin.Context, err error) bool {
+	if errJSON, ok := err.(*models.ErrorJson); ok && errJSON.Title == common.ErrCompanyAlreadyExists {
+		c.AbortWithStatusJSON(http.StatusConflict, errJSON)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"facturaexpress/pdftemplate"
+	"facturaexpress/ubl"
+	"fmt"
+	"time"
+)
+
+// errPDFGeneration marca los errores de un PDF que no vienen de la base de
+// datos: una plantilla guardada que no se puede leer o un documento que no se
+// pudo dibujar.
+var errPDFGeneration = errors.New("error al generar el PDF")
+
+// invoicePDF es lo necesario para dibujar el PDF de una factura. etag
+// identifica el PDF que resulta y lastModified es el último cambio de la
+// factura, de su plantilla o de las imágenes del perfil de su dueño.
+type invoicePDF struct {
+	tpl          pdftemplate.Template
+	data         pdftemplate.Data
+	etag         string
+	lastModified time.Time
+}
+
+// prepareInvoicePDF arma los datos del PDF con la plantilla asignada al
+// dueño de la factura, el código QR y las imágenes de su perfil.
+func (h *InvoiceHandler) prepareInvoicePDF(invoice models.Invoice) (invoicePDF, error) {
+	// The layout comes from the template assigned to the invoice owner, so every branch keeps its own
+	tpl, err := pdftemplate.ForUser(h.templates, invoice.UserID, h.fontDir)
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		return invoicePDF{}, fmt.Errorf("%w: plantilla del usuario %d: %s %v", errPDFGeneration, invoice.UserID, errJSON.Message, errJSON.Details)
+	}
+	if err != nil {
+		return invoicePDF{}, err
+	}
+
+	// The QR code with the DIAN verification URL is only printed once the invoice has a CUFE
+	data := pdftemplate.Data{Invoice: invoice}
+	if invoice.CUFE != "" {
+		data.QRContent = ubl.QRCodeContent(invoice, h.dian)
+		data.VerificationURL = ubl.VerificationURL(invoice.CUFE, h.dian)
+	}
+
+	// The owner's logo and signature are drawn by the template's image blocks
+	images, err := h.profiles.Images(invoice.UserID)
+	if err != nil {
+		return invoicePDF{}, err
+	}
+	data.SetImages(images)
+
+	// The fingerprint changes with any change to the invoice or its template, so it is both the ETag and the cache key
+	etag, err := pdftemplate.Fingerprint(tpl, data)
+	if err != nil {
+		return invoicePDF{}, fmt.Errorf("%w: %w", errPDFGeneration, err)
+	}
+	lastModified := invoice.UpdatedAt
+	if tpl.UpdatedAt.After(lastModified) {
+		lastModified = tpl.UpdatedAt
+	}
+	for _, image := range images {
+		if image.UpdatedAt.After(lastModified) {
+			lastModified = image.UpdatedAt
+		}
+	}
+	return invoicePDF{tpl: tpl, data: data, etag: etag, lastModified: lastModified}, nil
+}
+
+// renderInvoicePDF devuelve el PDF desde la caché o lo genera y lo guarda en ella.
+func (h *InvoiceHandler) renderInvoicePDF(p invoicePDF) ([]byte, error) {
+	if pdf, ok := h.pdfCache.Get(p.etag); ok {
+		return pdf, nil
+	}
+	var rendered bytes.Buffer
+	if err := pdftemplate.Render(p.tpl, p.data, &rendered); err != nil {
+		return nil, fmt.Errorf("%w: %w", errPDFGeneration, err)
+	}
+	h.pdfCache.Add(p.etag, rendered.Bytes())
+	return rendered.Bytes(), nil
+}
+
+// pdfErrorResponse es la respuesta para un error de prepareInvoicePDF o
+// renderInvoicePDF: PDF_GENERATION_FAILED si falló la plantilla o el dibujo
+// del documento y DB_ERROR si falló la consulta de sus datos.
+func pdfErrorResponse(err error) *models.ErrorJson {
+	if errors.Is(err, errPDFGeneration) {
+		return models.ErrorResponseInit(common.ErrPDFGenerationFailed, "No se pudo generar el PDF con la plantilla asignada.")
+	}
+	return models.ErrorResponseInit(common.ErrDBError, "Error al obtener la plantilla o las imágenes del PDF.")
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"facturaexpress/ubl"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IssueInvoice emite un borrador: le asigna el siguiente número de la
+// numeración de su dueño y, si hay clave técnica configurada, su CUFE.
+func (h *InvoiceHandler) IssueInvoice(c *gin.Context) {
+	invoice, ok := h.loadAuthorizedInvoice(c, "No tienes permiso para emitir esta factura.")
+	if !ok {
+		return
+	}
+	event, ok := bindStatusChange(c, invoice, models.InvoiceStatusIssued, false)
+	if !ok {
+		return
+	}
+
+	// El CUFE depende del número, por eso se calcula dentro de la misma transacción
+	stamp := func(invoice *models.Invoice) error {
+		if h.dian.TechnicalKey == "" {
+			return nil
+		}
+		cufe, err := ubl.CUFE(*invoice, h.dian)
+		if err != nil {
+			return models.ErrorResponseInit(common.ErrInvalidData, "No se pudo calcular el CUFE de la factura: "+err.Error())
+		}
+		invoice.CUFE = cufe
+		return nil
+	}
+
+	issued, err := h.invoices.Issue(int64(invoice.ID), event, stamp)
+
+	// Avisar si la resolución está por vencer o quedan pocos números
+	warnings := []string{}
+	if numbering, numberingErr := h.numberings.GetByUserID(invoice.UserID); numberingErr == nil {
+		warnings = numbering.Warnings(models.NowInBogota())
+	}
+
+	respondStatusChange(c, issued, err, "Factura emitida correctamente", gin.H{"advertencias": warnings})
+}
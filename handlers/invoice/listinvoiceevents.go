@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListInvoiceEvents devuelve el historial de cambios de estado de una factura.
+func (h *InvoiceHandler) ListInvoiceEvents(c *gin.Context) {
+	invoice, ok := h.loadAuthorizedInvoice(c, "No tienes permiso para consultar esta factura.")
+	if !ok {
+		return
+	}
+
+	events, err := h.invoices.ListEvents(int64(invoice.ID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener el historial de la factura."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"estado": invoice.Status, "eventos": events})
+}
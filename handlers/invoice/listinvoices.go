@@ -1,12 +1,9 @@
 package handlers
 
 import (
-	"database/sql"
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/helpers"
 	"facturaexpress/models"
-	"fmt"
 	"math"
 	"net/http"
 	"strconv"
@@ -14,7 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func ListInvoices(c *gin.Context) {
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
 	// Obtener el rol del usuario del token JWT
 	claims := c.MustGet("claims").(*models.Claims)
 	rol := claims.Role
@@ -46,75 +43,31 @@ func ListInvoices(c *gin.Context) {
 	if offset < 0 {
 		offset = 0
 	}
-	filterField := c.Query("filter_field")
-	filterValue := c.Query("filter_value")
-
-	// Construir y ejecutar la consulta para obtener las facturas
-	var rows *sql.Rows
-	var query string
-	var args []interface{}
-	if filterField != "" && filterValue != "" {
-		query = fmt.Sprintf(`SELECT * FROM facturas WHERE %s = $1 ORDER BY id ASC LIMIT $2 OFFSET $3`, filterField)
-		args = []interface{}{filterValue, limit, offset}
-	} else {
-		if rol == common.ADMIN {
-			query = `SELECT * FROM facturas ORDER BY id ASC LIMIT $1 OFFSET $2`
-			args = []interface{}{limit, offset}
-		} else {
-			query = `SELECT * FROM facturas WHERE usuario_id = $1 ORDER BY id ASC LIMIT $2 OFFSET $3`
-			args = []interface{}{claims.UserID, limit, offset}
-		}
+	filter, err := helpers.ParseInvoiceFilter(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, err)
+		return
+	}
+	filter.Limit = limit
+	filter.Offset = offset
+	// Los usuarios que no son administradores solo ven sus propias facturas, sin importar los filtros
+	if rol != common.ADMIN {
+		filter.UserID = claims.UserID
 	}
 
-	db := data.GetInstance()
-
-	rows, err = db.Query(query, args...)
+	invoices, err := h.invoices.List(filter)
 	if err != nil {
+		if errJSON, ok := err.(*models.ErrorJson); ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, errJSON)
+			return
+		}
 		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener las facturas"))
 		c.Abort()
 		return
 	}
-	defer rows.Close()
-
-	// Procesar las filas y construir el arreglo de facturas
-	var invoices []models.Invoice
-	for rows.Next() {
-		var (
-			id, userID                                    int
-			companyName, companyTIN                       string
-			date                                          string
-			services                                      []byte
-			totalValue                                    float64
-			operatorName, documentType                    string
-			document, documentIssuanceCity                string
-			cellphone, bankAccountNumber, bankAccountType string
-			bank                                          string
-		)
 
-		err := rows.Scan(&id, &companyName, &companyTIN, &date, &services, &totalValue, &operatorName, &documentType, &document, &documentIssuanceCity, &cellphone, &bankAccountNumber, &bankAccountType, &bank, &userID)
-
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Ocurrió un error al leer los datos de la base de datos"))
-			return
-		}
-
-		servicesDeserialized, err := helpers.UnmarshalServices(services)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrServicesUnMarshalError, "Error al deserializar servicios"))
-			c.Abort()
-			return
-		}
-
-		invoice := models.Invoice{
-			ID:         id,
-			Company:    models.Company{Name: companyName, TIN: companyTIN},
-			Date:       helpers.FormatDateInSpanish(date),
-			Services:   servicesDeserialized,
-			TotalValue: totalValue,
-			Operator:   models.Operator{Name: operatorName, DocumentType: documentType, Document: document, DocumentIssuanceCity: documentIssuanceCity, Cellphone: cellphone, BankAccountNumber: bankAccountNumber, BankAccountType: bankAccountType, Bank: bank},
-			UserID:     int64(userID),
-		}
-		invoices = append(invoices, invoice)
+	for i := range invoices {
+		invoices[i].Date = helpers.FormatDateInSpanish(invoices[i].Date)
 	}
 
 	// Verificar si se encontraron facturas y contar el total de facturas
@@ -123,20 +76,7 @@ func ListInvoices(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrNotFound, "La página solicitada no existe"))
 		c.Abort()
 	default:
-		var totalInvoices int
-		if filterField != "" && filterValue != "" {
-			query = `SELECT COUNT(*) FROM facturas WHERE $1 = $2`
-			args = []interface{}{filterField, filterValue}
-		} else {
-			if rol == common.ADMIN {
-				query = `SELECT COUNT(*) FROM facturas`
-				args = []interface{}{}
-			} else {
-				query = `SELECT COUNT(*) FROM facturas WHERE usuario_id = $1`
-				args = []interface{}{claims.UserID}
-			}
-		}
-		err = db.QueryRow(query, args...).Scan(&totalInvoices)
+		totalInvoices, err := h.invoices.Count(filter)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al contar las facturas"))
 			c.Abort()
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"facturaexpress/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SendInvoice marca como enviada al cliente una factura emitida.
+func (h *InvoiceHandler) SendInvoice(c *gin.Context) {
+	invoice, ok := h.loadAuthorizedInvoice(c, "No tienes permiso para enviar esta factura.")
+	if !ok {
+		return
+	}
+	event, ok := bindStatusChange(c, invoice, models.InvoiceStatusSent, false)
+	if !ok {
+		return
+	}
+
+	sent, err := h.invoices.ChangeStatus(int64(invoice.ID), event)
+	respondStatusChange(c, sent, err, "Factura marcada como enviada", nil)
+}
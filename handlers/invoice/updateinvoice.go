@@ -1,22 +1,21 @@
 package handlers
 
 import (
-	"encoding/json"
+	"database/sql"
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/helpers"
 	"facturaexpress/models"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
-func UpdateInvoice(c *gin.Context) {
+func (h *InvoiceHandler) UpdateInvoice(c *gin.Context) {
 	// Get the role and user ID from the JWT token
 	claims := c.MustGet("claims").(*models.Claims)
 	role := claims.Role
 	userID := claims.UserID
-	invoiceID := c.Param("id")
 
 	// Check if the user has the necessary role to access the route
 	if !helpers.VerifyRole(role, []string{common.ADMIN, common.USER}) {
@@ -25,29 +24,59 @@ func UpdateInvoice(c *gin.Context) {
 		return
 	}
 
+	// Validate the value of the id parameter
+	invoiceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || invoiceID <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidID, "El valor del parámetro id debe ser un número entero positivo"))
+		c.Abort()
+		return
+	}
+
+	// The owner is needed to check permissions and to look up the invoice company
+	invoiceUserID, err := h.invoices.GetOwnerID(invoiceID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrInvoiceNotFound, "No se encontró la factura con el ID especificado"))
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener el ID del usuario de la factura."))
+		c.Abort()
+		return
+	}
+
 	// Add a condition to allow common.ADMIN role to update any invoice
-	if role != common.ADMIN {
+	if role != common.ADMIN && userID != invoiceUserID {
 		// Check if the user is trying to update their own invoice
-		invoiceUserID, err := helpers.GetUserIDFromInvoice(invoiceID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener el ID del usuario de la factura."))
-			c.Abort()
-			return
-		}
-		if userID != invoiceUserID {
-			c.JSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "Solo puedes actualizar tus propias facturas."))
-			c.Abort()
-			return
-		}
+		c.JSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "Solo puedes actualizar tus propias facturas."))
+		c.Abort()
+		return
+	}
+
+	// Solo los borradores se pueden modificar
+	if !h.requireDraft(c, invoiceID, "modificar") {
+		return
 	}
 
 	var invoice models.Invoice
-	err := c.BindJSON(&invoice)
+	err = c.BindJSON(&invoice)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
 		c.Abort()
 		return
 	}
+	// Number, status, payments and balance are owned by the server
+	invoice.ClearServerFields()
+
+	// The company is taken from the owner's company directory
+	if !h.resolveCompany(c, &invoice, invoiceUserID) {
+		return
+	}
+
+	// Lines that reference the catalog take from it the values they leave empty
+	if !h.applyCatalog(c, &invoice, invoiceUserID) {
+		return
+	}
 
 	// Validate input data
 	if invoice.Company.Name == "" || invoice.Company.TIN == "" || invoice.Date == "" || len(invoice.Services) == 0 {
@@ -56,17 +85,27 @@ func UpdateInvoice(c *gin.Context) {
 		return
 	}
 
-	servicesJSON, err := json.Marshal(invoice.Services)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrServicesMarshalError, "Error al codificar los servicios en formato JSON."))
+	// Validar y normalizar el NIT de la empresa y el documento del operador
+	if err := helpers.ValidateInvoiceParties(&invoice); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		c.Abort()
+		return
+	}
+	if err := helpers.ValidateDueDate(&invoice); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		c.Abort()
+		return
+	}
+
+	// Compute line subtotals and the invoice total on the server
+	if err := helpers.CalculateInvoiceTotals(&invoice, h.taxRates); err != nil {
+		c.JSON(calculationErrorStatus(err), err)
 		c.Abort()
 		return
 	}
 
 	// Check if the user exists
-	var userExists bool
-	db := data.GetInstance()
-	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM usuarios WHERE id = $1)", userID).Scan(&userExists)
+	userExists, err := h.users.Exists(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al verificar si el usuario existe."))
 		c.Abort()
@@ -78,25 +117,19 @@ func UpdateInvoice(c *gin.Context) {
 		return
 	}
 
-	query := `UPDATE facturas SET nombre_empresa = $1,nit_empresa = $2,
-			fecha = $3,servicios = $4,
-			valor_total = $5,nombre_operador = $6,
-			tipo_documento_operador = $7,
-			documento_operador = $8,
-			ciudad_expedicion_documento_operador = $9,
-			celular_operador = $10,
-			numero_cuenta_bancaria_operador = $11,
-			tipo_cuenta_bancaria_operador = $12,
-			banco_operador = $13 WHERE id = $14`
-	result, err := db.Exec(query, invoice.Company.Name, invoice.Company.TIN, invoice.Date, servicesJSON, invoice.TotalValue, invoice.Operator.Name, invoice.Operator.DocumentType, invoice.Operator.Document, invoice.Operator.DocumentIssuanceCity, invoice.Operator.Cellphone, invoice.Operator.BankAccountNumber, invoice.Operator.BankAccountType, invoice.Operator.Bank, invoiceID)
+	// A new company is added to the directory in the same transaction as the invoice
+	updated, err := h.invoices.Update(invoiceID, invoice)
+	if respondCompanyConflict(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al actualizar la factura en la base de datos."))
 		c.Abort()
 		return
 	}
 
-	if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
-		c.JSON(http.StatusOK, gin.H{"message": "Factura actualizada correctamente"})
+	if updated {
+		c.JSON(http.StatusOK, gin.H{"message": "Factura actualizada correctamente", "valor_total": invoice.TotalValue})
 	} else {
 		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrInvoiceNotFound, "No se encontró la factura con el ID especificado"))
 	}
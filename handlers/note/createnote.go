@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateNote emite una nota crédito o débito sobre una factura.
+func (h *NoteHandler) CreateNote(c *gin.Context) {
+	invoice, ok := h.loadAuthorizedInvoice(c, "No tienes permiso para emitir notas sobre esta factura.")
+	if !ok {
+		return
+	}
+
+	var note models.Note
+	if err := c.BindJSON(&note); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	note.InvoiceID = int64(invoice.ID)
+
+	if err := note.Validate(time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	if err := models.CheckNoteAllowed(invoice.Status); err != nil {
+		c.JSON(http.StatusConflict, err)
+		return
+	}
+
+	// Las líneas y los totales de la nota los calcula el servidor
+	if err := helpers.CalculateNoteTotals(&note, h.taxRates); err != nil {
+		status := http.StatusBadRequest
+		if errJSON, ok := err.(*models.ErrorJson); ok && errJSON.Title == common.ErrTotalMismatch {
+			status = http.StatusUnprocessableEntity
+		}
+		c.JSON(status, err)
+		return
+	}
+
+	err := h.notes.Create(&note)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrInvoiceNotFound, "No se encontró la factura con el ID especificado."))
+		return
+	}
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		status := http.StatusUnprocessableEntity
+		if errJSON.Title == common.ErrNoteNotAllowed {
+			status = http.StatusConflict
+		}
+		c.JSON(status, errJSON)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al guardar la nota."))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": note.Title() + " creada correctamente", "note": note})
+}
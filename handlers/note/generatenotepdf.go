@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"facturaexpress/pdftemplate"
+	"facturaexpress/taxes"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GenerateNotePDF genera el PDF de una nota con la referencia a la factura que corrige.
+func (h *NoteHandler) GenerateNotePDF(c *gin.Context) {
+	note, ok := h.loadAuthorizedNote(c, "No tienes permiso para generar el archivo PDF de esta nota.")
+	if !ok {
+		return
+	}
+	invoice, err := h.invoices.GetByID(note.InvoiceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la factura de la nota."))
+		return
+	}
+
+	// The note uses the page, fonts and city of the invoice owner's PDF template
+	tpl, err := pdftemplate.ForUser(h.templates, invoice.UserID, h.fontDir)
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		log.Printf("PDF de la nota %d: plantilla del usuario %d: %s %v", note.ID, invoice.UserID, errJSON.Message, errJSON.Details)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrPDFGenerationFailed, "No se pudo generar el PDF con la plantilla asignada."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la plantilla de PDF."))
+		return
+	}
+	pdf := pdftemplate.NewDocument(tpl)
+	pdf.AddPage()
+
+	// Add note information
+	pdf.SetFont(tpl.Font.Family, tpl.Font.Style, 12)
+	pdf.Cell(40, 10, tpl.Label("ciudad")+" "+helpers.FormatDateInSpanish(note.Date))
+	pdf.CellFormat(0, 10, note.Title()+" N° "+note.Number, "", 1, "R", false, 0, "")
+	pdf.Cell(40, 10, invoice.Company.Name)
+	pdf.Ln(10)
+	pdf.Cell(40, 10, "Nit: "+invoice.Company.TIN)
+	pdf.Ln(15)
+
+	// Add the reference to the corrected invoice and the DIAN reason
+	pdf.SetFont(tpl.Font.Family, tpl.Font.Style, 11)
+	reference := "Cuenta de cobro N° " + invoice.Number
+	if invoice.CUFE != "" {
+		reference += "\nCUFE: " + invoice.CUFE
+	}
+	pdf.MultiCell(0, 6, "Referencia: "+reference, "", "L", false)
+	pdf.MultiCell(0, 6, fmt.Sprintf("Concepto de corrección: %s - %s", note.ReasonCode, note.ReasonDescription), "", "L", false)
+	if note.Description != "" {
+		pdf.MultiCell(0, 6, note.Description, "", "L", false)
+	}
+	pdf.Ln(5)
+
+	// Add issuer information
+	pdf.SetFont(tpl.Font.Family, tpl.Font.Style, 12)
+	pdf.Cell(40, 10, invoice.Operator.Name)
+	pdf.Ln(10)
+	pdf.Cell(40, 10, invoice.Operator.DocumentType+": "+invoice.Operator.Document)
+	pdf.Ln(15)
+
+	// Add the note lines
+	pdf.Cell(40, 10, "Por concepto de:")
+	pdf.Ln(10)
+	for _, service := range note.Services {
+		pdf.Cell(80, 10, service.Description)
+		pdf.Ln(7)
+		pdf.SetFont(tpl.Font.Family, tpl.Font.Style, 10)
+		breakdown := fmt.Sprintf("%s %s x $ %s", strconv.FormatFloat(service.Quantity, 'f', -1, 64), service.Unit, service.UnitPrice.Format())
+		if service.Discount > 0 {
+			breakdown += fmt.Sprintf(" - descuento $ %s", service.Discount.Format())
+		}
+		breakdown += fmt.Sprintf(" = $ %s", service.Subtotal.Format())
+		if service.IVAAmount > 0 {
+			breakdown += fmt.Sprintf(" + IVA %s%% $ %s", service.IVARate, service.IVAAmount.Format())
+		}
+		pdf.Cell(80, 8, strings.Join(strings.Fields(breakdown), " "))
+		pdf.SetFont(tpl.Font.Family, tpl.Font.Style, 12)
+		pdf.Ln(10)
+	}
+	pdf.Ln(5)
+
+	// Add the summary: subtotal, IVA by rate and total
+	summaryLine := func(label string, value models.Money) {
+		pdf.CellFormat(120, 8, label, "", 0, "L", false, 0, "")
+		pdf.CellFormat(50, 8, "$ "+value.Format(), "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont(tpl.Font.Family, tpl.Font.Style, 11)
+	summaryLine("Subtotal", note.Subtotal)
+	for _, tax := range note.Taxes {
+		if tax.Type == taxes.TypeIVA && tax.Amount > 0 {
+			summaryLine(fmt.Sprintf("%s sobre $ %s", tax.Description, tax.Base.Format()), tax.Amount)
+		}
+	}
+	pdf.SetFont(tpl.Font.Family, tpl.Font.Style, 12)
+	summaryLine("Total "+strings.ToLower(note.Title()), note.Total)
+	pdf.Ln(5)
+	pdf.MultiCell(0, 7, note.AmountInWords, "", "L", false)
+
+	if err := pdf.Error(); err != nil {
+		log.Printf("PDF de la nota %d: %v", note.ID, err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrPDFGenerationFailed, "No se pudo generar el PDF con la plantilla asignada."))
+		return
+	}
+
+	// Stream the PDF straight to the response
+	c.Header("Content-Type", "application/pdf")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, note.Number))
+	c.Status(http.StatusOK)
+	if err := pdf.Output(c.Writer); err != nil {
+		log.Printf("PDF de la nota %d: %v", note.ID, err)
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrPDFGenerationFailed, "No se pudo generar el PDF con la plantilla asignada."))
+		}
+	}
+}
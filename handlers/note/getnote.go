@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNote devuelve una nota en JSON. Solo el dueño de la factura o un administrador pueden consultarla.
+func (h *NoteHandler) GetNote(c *gin.Context) {
+	note, ok := h.loadAuthorizedNote(c, "No tienes permiso para consultar esta nota.")
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, note)
+}
+
+// loadAuthorizedNote obtiene la nota indicada en el parámetro id y verifica
+// que el usuario autenticado sea el dueño de la factura o tenga el rol ADMIN.
+// Si algo falla responde al cliente y devuelve false.
+func (h *NoteHandler) loadAuthorizedNote(c *gin.Context, forbiddenMessage string) (models.Note, bool) {
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return models.Note{}, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidID, "El valor del parámetro id debe ser un número entero positivo"))
+		return models.Note{}, false
+	}
+
+	note, err := h.notes.GetByID(id)
+	if err == sql.ErrNoRows {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrNoteNotFound, "No se encontró la nota con el ID especificado."))
+		return note, false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la nota."))
+		return note, false
+	}
+
+	if note.UserID != claims.UserID && claims.Role != common.ADMIN {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, forbiddenMessage))
+		return note, false
+	}
+	return note, true
+}
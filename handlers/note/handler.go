@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"facturaexpress/interfaces"
+	"facturaexpress/taxes"
+)
+
+// NoteHandler agrupa los controladores de notas crédito y débito.
+type NoteHandler struct {
+	notes     interfaces.NoteRepository
+	invoices  interfaces.InvoiceRepository
+	templates interfaces.PDFTemplateRepository
+	fontDir   string
+	taxRates  taxes.Rates
+}
+
+func NewNoteHandler(notes interfaces.NoteRepository, invoices interfaces.InvoiceRepository, templates interfaces.PDFTemplateRepository, fontDir string, taxRates taxes.Rates) *NoteHandler {
+	return &NoteHandler{notes: notes, invoices: invoices, templates: templates, fontDir: fontDir, taxRates: taxRates}
+}
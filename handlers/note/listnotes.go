@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListNotes devuelve las notas de una factura junto con su saldo ajustado.
+func (h *NoteHandler) ListNotes(c *gin.Context) {
+	invoice, ok := h.loadAuthorizedInvoice(c, "No tienes permiso para consultar esta factura.")
+	if !ok {
+		return
+	}
+
+	notes, err := h.notes.ListByInvoice(int64(invoice.ID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener las notas de la factura."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"factura_id":          invoice.ID,
+		"valor_neto":          invoice.NetPayable,
+		"total_notas_credito": invoice.TotalCredits,
+		"total_notas_debito":  invoice.TotalDebits,
+		"valor_ajustado":      invoice.AdjustedTotal,
+		"notas":               notes,
+	})
+}
+
+// loadAuthorizedInvoice obtiene la factura indicada en el parámetro id si el
+// usuario autenticado es su dueño o administrador; si no, responde al cliente y devuelve false.
+func (h *NoteHandler) loadAuthorizedInvoice(c *gin.Context, forbiddenMessage string) (models.Invoice, bool) {
+	return helpers.LoadAuthorizedInvoice(c, h.invoices, forbiddenMessage)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNumbering devuelve la numeración del usuario autenticado con sus advertencias de vencimiento.
+func (h *NumberingHandler) GetNumbering(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+
+	numbering, err := h.numberings.GetByUserID(claims.UserID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrNumberingNotConfigured, "Aún no has configurado la numeración de tu resolución de facturación."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al consultar la numeración."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"numeracion":   numbering,
+		"siguiente":    numbering.FormatNumber(numbering.Current + 1),
+		"disponibles":  numbering.Remaining(),
+		"advertencias": numbering.Warnings(models.NowInBogota()),
+	})
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import "facturaexpress/interfaces"
+
+// NumberingHandler agrupa los controladores de la numeración de facturas.
+type NumberingHandler struct {
+	numberings interfaces.NumberingRepository
+}
+
+func NewNumberingHandler(numberings interfaces.NumberingRepository) *NumberingHandler {
+	return &NumberingHandler{numberings: numberings}
+}
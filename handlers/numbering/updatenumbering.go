@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateNumbering configura la resolución de facturación del usuario
+// autenticado. Si la resolución o el prefijo cambian, la numeración empieza en
+// rango_desde; si no, conserva el último consecutivo asignado.
+func (h *NumberingHandler) UpdateNumbering(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+
+	var numbering models.Numbering
+	if err := c.BindJSON(&numbering); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	numbering.UserID = claims.UserID
+	numbering.Prefix = strings.ToUpper(strings.TrimSpace(numbering.Prefix))
+	numbering.ResolutionNumber = strings.TrimSpace(numbering.ResolutionNumber)
+
+	// El consecutivo actual se decide al guardar, con la fila bloqueada
+	err := h.numberings.Save(&numbering)
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		c.JSON(http.StatusBadRequest, errJSON)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDatabaseSaveFailed, "Error al guardar la numeración."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Numeración actualizada correctamente",
+		"numeracion":   numbering,
+		"advertencias": numbering.Warnings(models.NowInBogota()),
+	})
+}
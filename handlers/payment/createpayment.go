@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePayment registra un pago total o parcial de una factura.
+func (h *PaymentHandler) CreatePayment(c *gin.Context) {
+	invoice, ok := helpers.LoadAuthorizedInvoice(c, h.invoices, "No tienes permiso para registrar pagos de esta factura.")
+	if !ok {
+		return
+	}
+
+	var payment models.Payment
+	if err := c.BindJSON(&payment); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	// Los campos de las reversas no se reciben del cliente
+	payment.InvoiceID = int64(invoice.ID)
+	payment.Reason = ""
+	payment.ReversalOf = nil
+	payment.UserID = c.MustGet("claims").(*models.Claims).UserID
+
+	if err := payment.Validate(models.NowInBogota()); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+
+	err := h.payments.Create(&payment)
+	if !respondPaymentError(c, err) {
+		return
+	}
+
+	h.respondWithInvoice(c, http.StatusCreated, "Pago registrado correctamente", payment)
+}
+
+// respondPaymentError responde al cliente el error de Create o Reverse y
+// devuelve false; si no hubo error devuelve true.
+func respondPaymentError(c *gin.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrPaymentNotFound, "No se encontró la factura o el pago indicado."))
+		return false
+	}
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		status := http.StatusUnprocessableEntity
+		if errJSON.Title == common.ErrPaymentNotAllowed || errJSON.Title == common.ErrPaymentAlreadyReversed {
+			status = http.StatusConflict
+		}
+		c.JSON(status, errJSON)
+		return false
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al registrar el pago."))
+	return false
+}
+
+// respondWithInvoice responde el movimiento junto con la factura actualizada,
+// que trae el nuevo saldo y, si cambió, el nuevo estado.
+func (h *PaymentHandler) respondWithInvoice(c *gin.Context, status int, message string, payment models.Payment) {
+	invoice, err := h.invoices.GetByID(payment.InvoiceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la factura."))
+		return
+	}
+	c.JSON(status, gin.H{
+		"message":     message,
+		"payment":     payment,
+		"saldo":       invoice.Balance,
+		"estado":      invoice.Status,
+		"estado_pago": invoice.PaymentStatus,
+	})
+}
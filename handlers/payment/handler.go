@@ -0,0 +1,13 @@
+package handlers
+
+import "facturaexpress/interfaces"
+
+// PaymentHandler agrupa los controladores del libro de pagos de las facturas.
+type PaymentHandler struct {
+	payments interfaces.PaymentRepository
+	invoices interfaces.InvoiceRepository
+}
+
+func NewPaymentHandler(payments interfaces.PaymentRepository, invoices interfaces.InvoiceRepository) *PaymentHandler {
+	return &PaymentHandler{payments: payments, invoices: invoices}
+}
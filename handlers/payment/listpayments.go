@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPayments devuelve el libro de pagos de una factura con su saldo.
+func (h *PaymentHandler) ListPayments(c *gin.Context) {
+	invoice, ok := helpers.LoadAuthorizedInvoice(c, h.invoices, "No tienes permiso para consultar los pagos de esta factura.")
+	if !ok {
+		return
+	}
+
+	payments, err := h.payments.ListByInvoice(int64(invoice.ID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener los pagos de la factura."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"factura_id":     invoice.ID,
+		"valor_ajustado": invoice.AdjustedTotal,
+		"total_pagado":   invoice.TotalPaid,
+		"saldo":          invoice.Balance,
+		"estado":         invoice.Status,
+		"estado_pago":    invoice.PaymentStatus,
+		"pagos":          payments,
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReversePayment anula un pago registrando un movimiento por el valor
+// contrario. El motivo es obligatorio.
+func (h *PaymentHandler) ReversePayment(c *gin.Context) {
+	invoice, ok := helpers.LoadAuthorizedInvoice(c, h.invoices, "No tienes permiso para reversar pagos de esta factura.")
+	if !ok {
+		return
+	}
+
+	paymentID, err := strconv.ParseInt(c.Param("paymentID"), 10, 64)
+	if err != nil || paymentID <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidID, "El valor del parámetro paymentID debe ser un número entero positivo"))
+		return
+	}
+	payment, err := h.payments.GetByID(paymentID)
+	if err == sql.ErrNoRows || (err == nil && payment.InvoiceID != int64(invoice.ID)) {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrPaymentNotFound, "No se encontró el pago indicado en esta factura."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener el pago."))
+		return
+	}
+
+	var request models.StatusChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil || strings.TrimSpace(request.Reason) == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrMissingFields, "Debes indicar el motivo de la reversa en el campo 'motivo'."))
+		return
+	}
+
+	reversal := models.Payment{
+		Date:   models.NowInBogota().Format(models.NumberingDateLayout),
+		Reason: strings.TrimSpace(request.Reason),
+		UserID: c.MustGet("claims").(*models.Claims).UserID,
+	}
+	if !respondPaymentError(c, h.payments.Reverse(paymentID, &reversal)) {
+		return
+	}
+
+	h.respondWithInvoice(c, http.StatusCreated, "Pago reversado correctamente", reversal)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AssignPDFTemplate asigna una plantilla de PDF a un usuario con
+// {"plantilla_id": 3}; {"plantilla_id": null} le devuelve la predeterminada.
+// Las facturas del usuario se generan con su plantilla sin importar quién
+// descargue el PDF.
+func (h *PDFTemplateHandler) AssignPDFTemplate(c *gin.Context) {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || userID <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidUserID, "El valor del parámetro id debe ser un número entero positivo"))
+		return
+	}
+
+	var body struct {
+		TemplateID *int64 `json:"plantilla_id"`
+	}
+	if err := c.BindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	if body.TemplateID != nil {
+		_, err := h.templates.GetByID(*body.TemplateID)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrPDFTemplateNotFound, "No se encontró la plantilla con el ID especificado."))
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la plantilla."))
+			return
+		}
+	}
+
+	assigned, err := h.templates.AssignToUser(userID, body.TemplateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al asignar la plantilla."))
+		return
+	}
+	if !assigned {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrUserNotFound, "No se encontró el usuario con el ID especificado."))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Plantilla asignada correctamente", "plantilla_id": body.TemplateID})
+}
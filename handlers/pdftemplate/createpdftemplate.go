@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"facturaexpress/pdftemplate"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePDFTemplate registra una plantilla de PDF. El diseño se valida
+// aplicándolo a una factura de ejemplo antes de guardarlo.
+func (h *PDFTemplateHandler) CreatePDFTemplate(c *gin.Context) {
+	template, ok := h.bindTemplate(c)
+	if !ok {
+		return
+	}
+
+	if !saveTemplate(c, func() error { return h.templates.Create(&template) }) {
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message": "Plantilla creada correctamente", "template": template})
+}
+
+// bindTemplate lee la plantilla del cuerpo de la solicitud y valida su
+// nombre y su diseño. Si algo falla responde al cliente y devuelve false.
+func (h *PDFTemplateHandler) bindTemplate(c *gin.Context) (models.PDFTemplate, bool) {
+	var template models.PDFTemplate
+	if err := c.BindJSON(&template); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return template, false
+	}
+	template.Name = strings.Join(strings.Fields(template.Name), " ")
+	template.Description = strings.TrimSpace(template.Description)
+	if template.Name == "" {
+		c.JSON(http.StatusBadRequest, models.ValidationErrorInit(common.ErrInvalidPDFTemplate, "Revisa los datos de la plantilla.", []models.FieldError{{Field: "nombre", Message: "Es obligatorio."}}))
+		return template, false
+	}
+	if _, err := pdftemplate.Parse(template.Layout, h.fontDir); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return template, false
+	}
+	return template, true
+}
+
+// saveTemplate ejecuta save y responde el error si lo hay: 409 si el nombre
+// ya existe. Devuelve false si respondió un error.
+func saveTemplate(c *gin.Context, save func() error) bool {
+	err := save()
+	if errJSON, ok := err.(*models.ErrorJson); ok {
+		c.JSON(http.StatusConflict, errJSON)
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al guardar la plantilla."))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeletePDFTemplate elimina una plantilla de PDF. Los usuarios que la tenían
+// asignada vuelven a la predeterminada.
+func (h *PDFTemplateHandler) DeletePDFTemplate(c *gin.Context) {
+	template, ok := h.loadTemplate(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.templates.Delete(template.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al eliminar la plantilla."))
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrPDFTemplateNotFound, "No se encontró la plantilla con el ID especificado."))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Plantilla eliminada correctamente"})
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"encoding/json"
+	"facturaexpress/pdftemplate"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDefaultPDFTemplate devuelve el diseño incluido en la aplicación, que se
+// usa cuando no hay plantillas y sirve de punto de partida para escribir otras.
+func (h *PDFTemplateHandler) GetDefaultPDFTemplate(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"diseno": json.RawMessage(pdftemplate.DefaultLayout())})
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPDFTemplate devuelve una plantilla de PDF con su diseño.
+func (h *PDFTemplateHandler) GetPDFTemplate(c *gin.Context) {
+	template, ok := h.loadTemplate(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// loadTemplate obtiene la plantilla indicada en el parámetro id. Si algo
+// falla responde al cliente y devuelve false.
+func (h *PDFTemplateHandler) loadTemplate(c *gin.Context) (models.PDFTemplate, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidID, "El valor del parámetro id debe ser un número entero positivo"))
+		return models.PDFTemplate{}, false
+	}
+
+	template, err := h.templates.GetByID(id)
+	if err == sql.ErrNoRows {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrPDFTemplateNotFound, "No se encontró la plantilla con el ID especificado."))
+		return template, false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la plantilla."))
+		return template, false
+	}
+	return template, true
+}
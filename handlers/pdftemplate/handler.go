@@ -0,0 +1,13 @@
+package handlers
+
+import "facturaexpress/interfaces"
+
+// PDFTemplateHandler agrupa los controladores de administración de las plantillas de PDF.
+type PDFTemplateHandler struct {
+	templates interfaces.PDFTemplateRepository
+	fontDir   string
+}
+
+func NewPDFTemplateHandler(templates interfaces.PDFTemplateRepository, fontDir string) *PDFTemplateHandler {
+	return &PDFTemplateHandler{templates: templates, fontDir: fontDir}
+}
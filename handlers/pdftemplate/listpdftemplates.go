@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPDFTemplates lista las plantillas de PDF, primero la predeterminada.
+func (h *PDFTemplateHandler) ListPDFTemplates(c *gin.Context) {
+	templates, err := h.templates.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener las plantillas de PDF."))
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
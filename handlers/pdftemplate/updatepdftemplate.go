@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdatePDFTemplate reemplaza una plantilla de PDF. Los PDF que se generen
+// desde ese momento usan el nuevo diseño.
+func (h *PDFTemplateHandler) UpdatePDFTemplate(c *gin.Context) {
+	current, ok := h.loadTemplate(c)
+	if !ok {
+		return
+	}
+	template, ok := h.bindTemplate(c)
+	if !ok {
+		return
+	}
+	template.ID = current.ID
+
+	updated := false
+	if !saveTemplate(c, func() (err error) { updated, err = h.templates.Update(template); return err }) {
+		return
+	}
+	if !updated {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrPDFTemplateNotFound, "No se encontró la plantilla con el ID especificado."))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Plantilla actualizada correctamente", "template": template})
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAgingReport devuelve la cartera pendiente por empresa en rangos de 0 a
+// 30, 31 a 60, 61 a 90 y más de 90 días, en JSON o en CSV. Parámetros:
+//
+//	fecha_corte          fecha a la que se calcula la antigüedad (AAAA-MM-DD, hoy por defecto)
+//	base                 fecha desde la que se cuentan los días: vencimiento (por defecto) o fecha
+//	documento_operador   documento exacto del operador
+//	usuario_id           dueño de las facturas (solo lo respeta el administrador)
+//	formato              json (por defecto) o csv; también se acepta Accept: text/csv
+func (h *ReportHandler) GetAgingReport(c *gin.Context) {
+	// Los usuarios que no son administradores solo ven su propia cartera, igual que en el listado de facturas
+	claims := c.MustGet("claims").(*models.Claims)
+	if !helpers.VerifyRole(claims.Role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return
+	}
+
+	filter := models.AgingFilter{
+		OperatorDocument: strings.TrimSpace(c.Query("documento_operador")),
+		AsOf:             strings.TrimSpace(c.Query("fecha_corte")),
+		Basis:            strings.TrimSpace(c.DefaultQuery("base", models.AgingBasisDueDate)),
+	}
+	if filter.AsOf == "" {
+		filter.AsOf = time.Now().Format(models.NumberingDateLayout)
+	} else if _, err := time.Parse(models.NumberingDateLayout, filter.AsOf); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro 'fecha_corte' debe tener el formato AAAA-MM-DD."))
+		return
+	}
+	if filter.Basis != models.AgingBasisDueDate && filter.Basis != models.AgingBasisDate {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro 'base' debe ser 'vencimiento' o 'fecha'."))
+		return
+	}
+	if claims.Role == common.ADMIN {
+		if userIDStr := strings.TrimSpace(c.Query("usuario_id")); userIDStr != "" {
+			userID, err := strconv.ParseInt(userIDStr, 10, 64)
+			if err != nil || userID <= 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro 'usuario_id' debe ser un número entero positivo."))
+				return
+			}
+			filter.UserID = userID
+		}
+	} else {
+		filter.UserID = claims.UserID
+	}
+
+	report, err := h.reports.Aging(filter)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al generar el informe de cartera."))
+		return
+	}
+
+	var totals models.AgingBuckets
+	for _, row := range report {
+		totals.Add(row.AgingBuckets)
+	}
+
+	format := strings.ToLower(c.Query("formato"))
+	if format == "csv" || (format == "" && strings.Contains(c.GetHeader("Accept"), "text/csv")) {
+		writeAgingCSV(c, filter, report, totals)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"fecha_corte": filter.AsOf,
+		"base":        filter.Basis,
+		"empresas":    report,
+		"totales":     totals,
+	})
+}
+
+// writeAgingCSV escribe el informe con una fila por empresa y una fila final de totales.
+func writeAgingCSV(c *gin.Context, filter models.AgingFilter, report []models.AgingRow, totals models.AgingBuckets) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="cartera-`+filter.AsOf+`.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"nit_empresa", "nombre_empresa", "facturas", "0_30", "31_60", "61_90", "mas_de_90", "total"})
+	invoices := 0
+	for _, row := range report {
+		invoices += row.Invoices
+		writer.Write(agingCSVRecord(row.CompanyTIN, row.CompanyName, row.Invoices, row.AgingBuckets))
+	}
+	writer.Write(agingCSVRecord("TOTAL", "", invoices, totals))
+	writer.Flush()
+}
+
+func agingCSVRecord(tin, name string, invoices int, buckets models.AgingBuckets) []string {
+	return []string{
+		tin,
+		name,
+		strconv.Itoa(invoices),
+		buckets.Days0To30.String(),
+		buckets.Days31To60.String(),
+		buckets.Days61To90.String(),
+		buckets.Over90.String(),
+		buckets.Total.String(),
+	}
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import "facturaexpress/interfaces"
+
+// ReportHandler agrupa los controladores de informes.
+type ReportHandler struct {
+	reports interfaces.ReportRepository
+}
+
+func NewReportHandler(reports interfaces.ReportRepository) *ReportHandler {
+	return &ReportHandler{reports: reports}
+}
@@ -0,0 +1,13 @@
+package handlers
+
+import "facturaexpress/interfaces"
+
+// RoleHandler agrupa los controladores de roles.
+type RoleHandler struct {
+	users interfaces.UserRepository
+	roles interfaces.RoleRepository
+}
+
+func NewRoleHandler(users interfaces.UserRepository, roles interfaces.RoleRepository) *RoleHandler {
+	return &RoleHandler{users: users, roles: roles}
+}
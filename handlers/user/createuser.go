@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/helpers"
 	"facturaexpress/models"
 	"net/http"
@@ -11,16 +10,14 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func CreateUser(c *gin.Context) {
+func (h *UserHandler) CreateUser(c *gin.Context) {
 	var user models.User
 	if err := c.ShouldBindJSON(&user); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrJSONBindingFailed, "Error al procesar los datos del usuario."))
 		return
 	}
 
-	db := data.GetInstance()
-
-	if err := helpers.CheckUsernameEmail(db, user.Username, user.Email); err != nil {
+	if err := helpers.CheckUsernameEmail(h.users, user.Username, user.Email); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -31,8 +28,7 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
-	query := "INSERT INTO usuarios (nombre_usuario, password, correo) VALUES ($1, $2, $3) RETURNING id"
-	err = db.QueryRow(query, user.Username, hashedPassword, user.Email).Scan(&user.ID)
+	user.ID, err = h.users.Create(user.Username, hashedPassword, user.Email)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrQueryFailed, "Error al ejecutar la consulta."))
 		return
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteProfileImage elimina el logo o la firma del usuario autenticado. Los
+// PDF que se generen después ya no la incluyen.
+func (h *UserHandler) DeleteProfileImage(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+
+	deleted, err := h.profiles.DeleteImage(claims.UserID, c.Param("tipo"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al eliminar la imagen del perfil."))
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrProfileImageNotFound, "No tienes una imagen de ese tipo en tu perfil."))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Imagen eliminada correctamente"})
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/models"
 	"net/http"
 	"strconv"
@@ -10,7 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func DeleteUser(c *gin.Context) {
+func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -18,22 +17,14 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
-	db := data.GetInstance()
-
-	query := "DELETE FROM usuarios WHERE id = $1"
-	result, err := db.Exec(query, id)
+	deleted, err := h.users.Delete(id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
-	count, err := result.RowsAffected()
-	if count == 0 {
+	if !deleted {
 		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrNotFound, "No se encontró el usuario con el ID especificado."))
 		return
 	}
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, err.Error())
-		return
-	}
 	c.JSON(http.StatusOK, gin.H{"message": "El registro ha sido eliminado correctamente."})
 }
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProfileImage devuelve el logo o la firma del usuario autenticado tal como se subió.
+func (h *UserHandler) GetProfileImage(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+
+	image, err := h.profiles.GetImage(claims.UserID, c.Param("tipo"))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrProfileImageNotFound, "No tienes una imagen de ese tipo en tu perfil."))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la imagen del perfil."))
+		return
+	}
+
+	etag := helpers.ComputeETag(image.Content)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if helpers.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, image.ContentType, image.Content)
+}
@@ -1,16 +1,19 @@
 package handlers
 
 import (
-	"facturaexpress/data"
 	"facturaexpress/models"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func GetUserInfo(c *gin.Context) {
-	db := data.GetInstance()
+// userProfileResponse agrega al usuario su perfil de operador.
+type userProfileResponse struct {
+	models.User
+	Profile models.OperatorProfile `json:"perfil_operador"`
+}
 
+func (h *UserHandler) GetUserInfo(c *gin.Context) {
 	// Obtener el ID del usuario autenticado y su rol
 	claims := c.MustGet("claims").(*models.Claims)
 	userID := claims.UserID
@@ -23,17 +26,18 @@ func GetUserInfo(c *gin.Context) {
 	}*/
 
 	// Consultar la información del usuario autenticado
-	row := db.QueryRow(`SELECT usuarios.id, usuarios.nombre_usuario, usuarios.correo, roles.name
-	FROM usuarios
-	INNER JOIN user_roles ON usuarios.id = user_roles.user_id
-	INNER JOIN roles ON user_roles.role_id = roles.id
-	WHERE usuarios.id = $1`, userID)
-	var user models.User
-	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Role)
+	user, err := h.users.GetByID(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit("SCAN_FAILED", "Error al escanear los resultados."))
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	// Incluir el perfil de operador con el que se llenan las facturas nuevas
+	profile, err := h.profiles.GetByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit("SCAN_FAILED", "Error al obtener el perfil de operador."))
+		return
+	}
+
+	c.JSON(http.StatusOK, userProfileResponse{User: user, Profile: profile})
 }
@@ -0,0 +1,13 @@
+package handlers
+
+import "facturaexpress/interfaces"
+
+// UserHandler agrupa los controladores de usuarios.
+type UserHandler struct {
+	users    interfaces.UserRepository
+	profiles interfaces.ProfileRepository
+}
+
+func NewUserHandler(users interfaces.UserRepository, profiles interfaces.ProfileRepository) *UserHandler {
+	return &UserHandler{users: users, profiles: profiles}
+}
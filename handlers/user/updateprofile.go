@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateProfile reemplaza el perfil de operador del usuario autenticado y sus
+// cuentas bancarias.
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+
+	var profile models.OperatorProfile
+	if err := c.BindJSON(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "Datos inválidos. Verifica y vuelve a intentarlo."))
+		return
+	}
+	if err := helpers.ValidateOperatorProfile(&profile); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	profile.UserID = claims.UserID
+
+	if err := h.profiles.Save(&profile); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDatabaseSaveFailed, "Error al guardar el perfil de operador."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Perfil actualizado correctamente", "perfil_operador": profile})
+}
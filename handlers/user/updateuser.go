@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/models"
 	"net/http"
 	"strconv"
@@ -11,7 +10,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func UpdateUser(c *gin.Context) {
+func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
@@ -31,22 +30,14 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	db := data.GetInstance()
-
-	query := "UPDATE usuarios SET nombre_usuario=$1, password=$2, correo=$3 WHERE id=$4"
-	result, err := db.Exec(query, &user.Username, hashedPassword, &user.Email, id)
+	updated, err := h.users.Update(id, user.Username, hashedPassword, user.Email)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, err.Error())
 		return
 	}
-	count, err := result.RowsAffected()
-	if count == 0 {
+	if !updated {
 		c.JSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrNotFound, "No se encontró el usuario con el ID especificado."))
 		return
 	}
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, err.Error())
-		return
-	}
 	c.JSON(http.StatusOK, gin.H{"message": "Los datos del usuario se han actualizado correctamente."})
 }
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadProfileImage crea o reemplaza el logo o la firma del usuario
+// autenticado. La imagen llega en el campo "imagen" de un formulario
+// multipart/form-data.
+func (h *UserHandler) UploadProfileImage(c *gin.Context) {
+	claims := c.MustGet("claims").(*models.Claims)
+
+	// El cuerpo se limita para no leer archivos enormes; el margen cubre los encabezados del formulario
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, helpers.MaxProfileImageSize+64<<10)
+	file, _, err := c.Request.FormFile("imagen")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ValidationErrorInit(common.ErrInvalidProfileImage, "Revisa la imagen del perfil.", []models.FieldError{{
+			Field:   "imagen",
+			Message: fmt.Sprintf("Envía una imagen PNG o JPEG de hasta %d KB en el campo imagen de un formulario multipart/form-data.", helpers.MaxProfileImageSize>>10),
+		}}))
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, helpers.MaxProfileImageSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidData, "No se pudo leer la imagen."))
+		return
+	}
+
+	image := models.ProfileImage{UserID: claims.UserID, Kind: c.Param("tipo"), Content: content}
+	if err := helpers.ValidateProfileImage(&image); err != nil {
+		c.JSON(http.StatusBadRequest, err)
+		return
+	}
+	if err := h.profiles.SaveImage(&image); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDatabaseSaveFailed, "Error al guardar la imagen del perfil."))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Imagen guardada correctamente", "imagen": image})
+}
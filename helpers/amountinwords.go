@@ -0,0 +1,116 @@
+package helpers
+
+import (
+	"facturaexpress/models"
+	"strings"
+)
+
+// AmountInWords escribe un monto en letras como se exige en una cuenta de
+// cobro, p. ej. "UN MILLÓN DOSCIENTOS MIL PESOS M/CTE" o
+// "DOS MILLONES DE PESOS CON CINCUENTA CENTAVOS M/CTE". Usa la escala larga:
+// mil millones y luego billones (10^12).
+func AmountInWords(amount models.Money) string {
+	cents := int64(amount.Round(models.DefaultCurrency))
+	prefix := ""
+	if cents < 0 {
+		prefix = "MENOS "
+		cents = -cents
+	}
+	pesos, centavos := cents/100, cents%100
+
+	var words string
+	switch {
+	case pesos == 0:
+		words = "CERO PESOS"
+	case pesos == 1:
+		words = "UN PESO"
+	case pesos%1000000 == 0:
+		// "UN MILLÓN DE PESOS", "DOS BILLONES DE PESOS"
+		words = integerInWords(pesos) + " DE PESOS"
+	default:
+		words = integerInWords(pesos) + " PESOS"
+	}
+
+	switch {
+	case centavos == 1:
+		words += " CON UN CENTAVO"
+	case centavos > 1:
+		words += " CON " + tensInWords(centavos) + " CENTAVOS"
+	}
+
+	return prefix + words + " M/CTE"
+}
+
+var unitWords = [...]string{
+	"", "UN", "DOS", "TRES", "CUATRO", "CINCO", "SEIS", "SIETE", "OCHO", "NUEVE",
+	"DIEZ", "ONCE", "DOCE", "TRECE", "CATORCE", "QUINCE", "DIECISÉIS", "DIECISIETE", "DIECIOCHO", "DIECINUEVE",
+	"VEINTE", "VEINTIÚN", "VEINTIDÓS", "VEINTITRÉS", "VEINTICUATRO", "VEINTICINCO", "VEINTISÉIS", "VEINTISIETE", "VEINTIOCHO", "VEINTINUEVE",
+}
+
+var tenWords = [...]string{"", "", "", "TREINTA", "CUARENTA", "CINCUENTA", "SESENTA", "SETENTA", "OCHENTA", "NOVENTA"}
+
+var hundredWords = [...]string{"", "CIENTO", "DOSCIENTOS", "TRESCIENTOS", "CUATROCIENTOS", "QUINIENTOS", "SEISCIENTOS", "SETECIENTOS", "OCHOCIENTOS", "NOVECIENTOS"}
+
+// integerInWords escribe un entero positivo con la forma apocopada ("UN",
+// "VEINTIÚN") porque siempre va seguido de un sustantivo.
+func integerInWords(n int64) string {
+	var parts []string
+	if trillions := n / 1000000000000; trillions > 0 {
+		if trillions == 1 {
+			parts = append(parts, "UN BILLÓN")
+		} else {
+			parts = append(parts, thousandsInWords(trillions)+" BILLONES")
+		}
+	}
+	if millions := n / 1000000 % 1000000; millions > 0 {
+		if millions == 1 {
+			parts = append(parts, "UN MILLÓN")
+		} else {
+			parts = append(parts, thousandsInWords(millions)+" MILLONES")
+		}
+	}
+	if rest := n % 1000000; rest > 0 {
+		parts = append(parts, thousandsInWords(rest))
+	}
+	return strings.Join(parts, " ")
+}
+
+// thousandsInWords escribe un número entre 1 y 999.999.
+func thousandsInWords(n int64) string {
+	var parts []string
+	if thousands := n / 1000; thousands == 1 {
+		parts = append(parts, "MIL")
+	} else if thousands > 1 {
+		parts = append(parts, hundredsInWords(thousands)+" MIL")
+	}
+	if rest := n % 1000; rest > 0 {
+		parts = append(parts, hundredsInWords(rest))
+	}
+	return strings.Join(parts, " ")
+}
+
+// hundredsInWords escribe un número entre 1 y 999.
+func hundredsInWords(n int64) string {
+	if n == 100 {
+		return "CIEN"
+	}
+	var parts []string
+	if hundreds := n / 100; hundreds > 0 {
+		parts = append(parts, hundredWords[hundreds])
+	}
+	if rest := n % 100; rest > 0 {
+		parts = append(parts, tensInWords(rest))
+	}
+	return strings.Join(parts, " ")
+}
+
+// tensInWords escribe un número entre 1 y 99.
+func tensInWords(n int64) string {
+	if n < 30 {
+		return unitWords[n]
+	}
+	if n%10 == 0 {
+		return tenWords[n/10]
+	}
+	return tenWords[n/10] + " Y " + unitWords[n%10]
+}
@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"facturaexpress/models"
+	"testing"
+)
+
+func TestAmountInWords(t *testing.T) {
+	tests := []struct {
+		amount models.Money
+		want   string
+	}{
+		{0, "CERO PESOS M/CTE"},
+		{1, "CERO PESOS CON UN CENTAVO M/CTE"},
+		{100, "UN PESO M/CTE"},
+		{150, "UN PESO CON CINCUENTA CENTAVOS M/CTE"},
+		{2100, "VEINTIÚN PESOS M/CTE"},
+		{10000, "CIEN PESOS M/CTE"},
+		{16000, "CIENTO SESENTA PESOS M/CTE"},
+		{100000, "MIL PESOS M/CTE"},
+		{115000, "MIL CIENTO CINCUENTA PESOS M/CTE"},
+		{2100000, "VEINTIÚN MIL PESOS M/CTE"},
+		{50050000, "QUINIENTOS MIL QUINIENTOS PESOS M/CTE"},
+		{100000000, "UN MILLÓN DE PESOS M/CTE"},
+		{120000000, "UN MILLÓN DOSCIENTOS MIL PESOS M/CTE"},
+		{200000050, "DOS MILLONES DE PESOS CON CINCUENTA CENTAVOS M/CTE"},
+		{2100000000, "VEINTIÚN MILLONES DE PESOS M/CTE"},
+		{100000000000, "MIL MILLONES DE PESOS M/CTE"},
+		{123456789012, "MIL DOSCIENTOS TREINTA Y CUATRO MILLONES QUINIENTOS SESENTA Y SIETE MIL OCHOCIENTOS NOVENTA PESOS CON DOCE CENTAVOS M/CTE"},
+		{100000000000000, "UN BILLÓN DE PESOS M/CTE"},
+		{200000000000000, "DOS BILLONES DE PESOS M/CTE"},
+		{-150000, "MENOS MIL QUINIENTOS PESOS M/CTE"},
+	}
+	for _, tt := range tests {
+		if got := AmountInWords(tt.amount); got != tt.want {
+			t.Errorf("AmountInWords(%s) = %q, se esperaba %q", tt.amount, got, tt.want)
+		}
+	}
+}
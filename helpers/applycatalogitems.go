@@ -0,0 +1,52 @@
+package helpers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"fmt"
+)
+
+// ApplyCatalogItems completa las líneas de una factura que referencian un
+// servicio del catálogo de su dueño con catalogo_id. La descripción, la
+// unidad, el valor unitario y la tarifa de IVA del catálogo se copian en los
+// campos que la línea trae vacíos, de modo que una línea puede cambiar, por
+// ejemplo, el precio sugerido. Un servicio inexistente o de otro usuario
+// devuelve un error CATALOG_ITEM_NOT_FOUND.
+func ApplyCatalogItems(services []models.Service, catalog interfaces.CatalogRepository, ownerID int64) error {
+	items := map[int64]models.CatalogItem{}
+	for i := range services {
+		service := &services[i]
+		if service.CatalogID == 0 {
+			continue
+		}
+
+		item, ok := items[service.CatalogID]
+		if !ok {
+			var err error
+			item, err = catalog.GetByID(service.CatalogID)
+			if err == sql.ErrNoRows || (err == nil && item.UserID != ownerID) {
+				return models.ErrorResponseInit(common.ErrCatalogItemNotFound, fmt.Sprintf("El servicio %d referencia el servicio del catálogo %d, que no existe.", i+1, service.CatalogID))
+			}
+			if err != nil {
+				return err
+			}
+			items[service.CatalogID] = item
+		}
+
+		if service.Description == "" {
+			service.Description = item.Description
+		}
+		if service.Unit == "" {
+			service.Unit = item.Unit
+		}
+		if service.UnitPrice == 0 {
+			service.UnitPrice = item.UnitPrice
+		}
+		if service.IVARate == "" {
+			service.IVARate = item.IVARate
+		}
+	}
+	return nil
+}
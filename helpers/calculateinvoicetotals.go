@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"facturaexpress/taxes"
+	"fmt"
+	"strings"
+)
+
+// CalculateInvoiceTotals calcula en el servidor el subtotal de cada servicio,
+// los impuestos y retenciones con las tarifas dadas y los totales de la
+// factura. Una cantidad omitida se toma como 1. Si el cliente envió
+// valor_total y no coincide con el calculado (subtotal más IVA), devuelve un
+// error TOTAL_MISMATCH en lugar de guardar un total que no cuadra con los servicios.
+func CalculateInvoiceTotals(invoice *models.Invoice, rates taxes.Rates) error {
+	if len(invoice.Services) == 0 {
+		return models.ErrorResponseInit(common.ErrMissingFields, "La factura debe tener al menos un servicio.")
+	}
+
+	for i := range invoice.Services {
+		service := &invoice.Services[i]
+		line := i + 1
+
+		if strings.TrimSpace(service.Description) == "" {
+			return models.ErrorResponseInit(common.ErrInvalidServiceLine, fmt.Sprintf("El servicio %d no tiene descripción.", line))
+		}
+		if service.Quantity == 0 {
+			service.Quantity = 1
+		}
+		if service.Quantity < 0 {
+			return models.ErrorResponseInit(common.ErrInvalidServiceLine, fmt.Sprintf("La cantidad del servicio %d debe ser mayor que cero.", line))
+		}
+		if service.UnitPrice < 0 {
+			return models.ErrorResponseInit(common.ErrInvalidServiceLine, fmt.Sprintf("El valor unitario del servicio %d no puede ser negativo.", line))
+		}
+
+		gross, err := service.UnitPrice.Multiply(service.Quantity)
+		if err != nil {
+			return models.AmountOutOfRange(fmt.Sprintf("servicios[%d]", i))
+		}
+		gross = gross.Round(models.DefaultCurrency)
+		if service.Discount < 0 || service.Discount > gross {
+			return models.ErrorResponseInit(common.ErrInvalidServiceLine, fmt.Sprintf("El descuento del servicio %d debe estar entre 0 y el valor bruto de la línea.", line))
+		}
+
+		service.Subtotal = gross - service.Discount
+	}
+
+	if err := validateWithholdingRates(invoice.Withholdings); err != nil {
+		return err
+	}
+
+	sentTotal := invoice.TotalValue
+	if err := taxes.Apply(invoice, rates); err != nil {
+		return err
+	}
+	// Cada tarifa cabe en el rango, pero varias retenciones juntas todavía pueden superar el total
+	if invoice.NetPayable < 0 {
+		return models.ValidationErrorInit(common.ErrInvalidTax, "Revisa las retenciones de la factura.", []models.FieldError{{Field: "retenciones", Message: "Las retenciones no pueden superar el valor total de la factura."}})
+	}
+
+	if sentTotal != 0 && sentTotal != invoice.TotalValue {
+		return models.ErrorResponseInit(common.ErrTotalMismatch, fmt.Sprintf("El valor total enviado (%s) no coincide con el calculado a partir de los servicios (%s).", sentTotal.FormatWithSymbol(models.DefaultCurrency), invoice.TotalValue.FormatWithSymbol(models.DefaultCurrency)))
+	}
+	invoice.AmountInWords = AmountInWords(invoice.NetPayable)
+	invoice.ComputeBalance()
+
+	return nil
+}
+
+// validateWithholdingRates revisa que las tarifas que reemplazan a las
+// configuradas estén entre 0 y 100; fuera de ese rango el valor neto quedaría
+// negativo o por encima del total.
+func validateWithholdingRates(withholdings []models.Withholding) error {
+	var details []models.FieldError
+	for i, withholding := range withholdings {
+		if withholding.Rate != nil && (*withholding.Rate < 0 || *withholding.Rate > 100) {
+			details = append(details, models.FieldError{Field: fmt.Sprintf("retenciones[%d].tarifa", i), Message: "La tarifa debe estar entre 0 y 100."})
+		}
+	}
+	if len(details) > 0 {
+		return models.ValidationErrorInit(common.ErrInvalidTax, "Revisa las tarifas de las retenciones.", details)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"facturaexpress/taxes"
+	"testing"
+)
+
+func ratePtr(value float64) *float64 {
+	return &value
+}
+
+func TestCalculateInvoiceTotals(t *testing.T) {
+	invoice := &models.Invoice{
+		Services: []models.Service{
+			{Description: "Horas de desarrollo", Quantity: 2.5, UnitPrice: 8000000, Discount: 1000000, IVARate: "19"},
+			{Description: "Visita", UnitPrice: 5000000},
+		},
+		Withholdings: []models.Withholding{{Type: taxes.TypeReteFuente, Concept: "honorarios"}},
+	}
+	if err := CalculateInvoiceTotals(invoice, taxes.DefaultRates()); err != nil {
+		t.Fatal(err)
+	}
+	if invoice.Services[0].Subtotal != 19000000 || invoice.Services[1].Quantity != 1 || invoice.Services[1].Subtotal != 5000000 {
+		t.Fatalf("servicios: %+v", invoice.Services)
+	}
+	// IVA 19 % de 190.000 y retención del 11 % sobre 240.000
+	if invoice.TotalValue != 27610000 || invoice.TotalWithholdings != 2640000 || invoice.NetPayable != 24970000 {
+		t.Fatalf("total %s, retenciones %s, neto %s", invoice.TotalValue, invoice.TotalWithholdings, invoice.NetPayable)
+	}
+	if invoice.AmountInWords != "DOSCIENTOS CUARENTA Y NUEVE MIL SETECIENTOS PESOS M/CTE" {
+		t.Errorf("valor en letras %q", invoice.AmountInWords)
+	}
+}
+
+func TestCalculateInvoiceTotalsRejectsWithholdingRatesOutOfRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		withholdings []models.Withholding
+		wantField    string
+	}{
+		{"negativa", []models.Withholding{{Type: taxes.TypeReteFuente, Concept: "honorarios", Rate: ratePtr(-5)}}, "retenciones[0].tarifa"},
+		{"mayor que 100", []models.Withholding{{Type: taxes.TypeReteIVA}, {Type: taxes.TypeReteICA, Municipality: "Cali", Rate: ratePtr(150)}}, "retenciones[1].tarifa"},
+		{"suman más que el total", []models.Withholding{{Type: taxes.TypeReteFuente, Concept: "honorarios", Rate: ratePtr(100)}, {Type: taxes.TypeReteICA, Municipality: "Cali", Rate: ratePtr(100)}}, "retenciones"},
+	}
+	for _, tt := range tests {
+		invoice := &models.Invoice{
+			Services:     []models.Service{{Description: "Servicio", UnitPrice: 100000000, IVARate: "19"}},
+			Withholdings: tt.withholdings,
+		}
+		err := CalculateInvoiceTotals(invoice, taxes.DefaultRates())
+		errorJson, ok := err.(*models.ErrorJson)
+		if !ok || errorJson.Title != common.ErrInvalidTax {
+			t.Errorf("%s: se esperaba INVALID_TAX, se obtuvo %v", tt.name, err)
+			continue
+		}
+		if len(errorJson.Details) != 1 || errorJson.Details[0].Field != tt.wantField {
+			t.Errorf("%s: detalles %+v, se esperaba el campo %s", tt.name, errorJson.Details, tt.wantField)
+		}
+	}
+
+	invoice := &models.Invoice{
+		Services:     []models.Service{{Description: "Servicio", UnitPrice: 100000000}},
+		Withholdings: []models.Withholding{{Type: taxes.TypeReteICA, Municipality: "Cali", Rate: ratePtr(100)}},
+	}
+	if err := CalculateInvoiceTotals(invoice, taxes.DefaultRates()); err != nil {
+		t.Errorf("una tarifa de 100 es válida: %v", err)
+	}
+}
+
+func TestCalculateInvoiceTotalsRejectsAmountsOutOfRange(t *testing.T) {
+	// 3.000 billones de pesos, cerca del máximo que cabe en models.Money
+	const large = models.Money(300000000000000000)
+	tests := []struct {
+		name      string
+		services  []models.Service
+		wantField string
+	}{
+		{"cantidad", []models.Service{{Description: "Servicio", Quantity: 1e12, UnitPrice: 100000000}}, "servicios[0]"},
+		{"subtotal", []models.Service{{Description: "Uno", Quantity: 10, UnitPrice: large}, {Description: "Dos", Quantity: 10, UnitPrice: large}}, "subtotal"},
+		{"IVA", []models.Service{{Description: "Servicio", Quantity: 15, UnitPrice: large, IVARate: "19"}}, "valor_total"},
+	}
+	for _, tt := range tests {
+		err := CalculateInvoiceTotals(&models.Invoice{Services: tt.services}, taxes.DefaultRates())
+		errorJson, ok := err.(*models.ErrorJson)
+		if !ok || errorJson.Title != common.ErrAmountOutOfRange {
+			t.Errorf("%s: se esperaba AMOUNT_OUT_OF_RANGE, se obtuvo %v", tt.name, err)
+			continue
+		}
+		if len(errorJson.Details) != 1 || errorJson.Details[0].Field != tt.wantField {
+			t.Errorf("%s: detalles %+v, se esperaba el campo %s", tt.name, errorJson.Details, tt.wantField)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"facturaexpress/taxes"
+)
+
+// CalculateNoteTotals calcula las líneas, el IVA y el total de una nota con
+// las mismas reglas que las de una factura. Las notas no llevan retenciones,
+// así que su total es el subtotal más el IVA. Si el cliente envió total y no
+// coincide con el calculado devuelve un error TOTAL_MISMATCH.
+func CalculateNoteTotals(note *models.Note, rates taxes.Rates) error {
+	lines := models.Invoice{Services: note.Services, TotalValue: note.Total}
+	if err := CalculateInvoiceTotals(&lines, rates); err != nil {
+		return err
+	}
+	if lines.TotalValue <= 0 {
+		return models.ErrorResponseInit(common.ErrInvalidServiceLine, "El total de la nota debe ser mayor que cero.")
+	}
+
+	note.Services = lines.Services
+	note.Taxes = lines.Taxes
+	note.Subtotal = lines.Subtotal
+	note.TotalIVA = lines.TotalIVA
+	note.Total = lines.TotalValue
+	note.AmountInWords = AmountInWords(note.Total)
+	return nil
+}
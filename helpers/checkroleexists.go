@@ -2,19 +2,12 @@ package helpers
 
 import (
 	"facturaexpress/common"
-	"facturaexpress/data"
+	"facturaexpress/interfaces"
 	"facturaexpress/models"
 )
 
-func CheckRoleExists(db *data.PostgresAdapter, roleName string) error {
-	stmt, err := db.Prepare(`SELECT COUNT(*) FROM roles WHERE name=$1`)
-	if err != nil {
-		return models.ErrorResponseInit(common.ErrQueryPreparationFailed, "Error al preparar la consulta.")
-	}
-	defer stmt.Close()
-	row := stmt.QueryRow(roleName)
-	var count int
-	if err = row.Scan(&count); err != nil || count == 0 {
+func CheckRoleExists(roles interfaces.RoleRepository, roleName string) error {
+	if _, err := roles.GetIDByName(roleName); err != nil {
 		return models.ErrorResponseInit(common.ErrRoleNotFound, "No se encontró el rol especificado.")
 	}
 	return nil
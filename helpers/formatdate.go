@@ -1,20 +1,20 @@
 package helpers
 
 import (
+	"facturaexpress/models"
 	"fmt"
-	"time"
 )
 
+// FormatDateInSpanish presenta la fecha de una factura o una nota como
+// "15 de Enero de 2024". La lee con models.ParseInvoiceDate, igual que el XML
+// y el CUFE, así que el día impreso es el mismo del documento electrónico. Si
+// la fecha no es válida la devuelve sin cambios.
 func FormatDateInSpanish(date_string string) string {
-	loc, err := time.LoadLocation("America/Bogota")
+	t, err := models.ParseInvoiceDate(date_string)
 	if err != nil {
 		fmt.Println(err)
+		return date_string
 	}
-	t, err := time.Parse(time.RFC3339, date_string)
-	if err != nil {
-		fmt.Println(err)
-	}
-	t = t.In(loc)
 	return fmt.Sprintf("%02d de %s de %d", t.Day(), Meses[t.Month()-1], t.Year())
 }
 
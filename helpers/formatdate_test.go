@@ -0,0 +1,21 @@
+package helpers
+
+import "testing"
+
+func TestFormatDateInSpanish(t *testing.T) {
+	tests := []struct {
+		date, want string
+	}{
+		// lib/pq marca como UTC la medianoche de Colombia guardada en el TIMESTAMP
+		{"2024-01-15T00:00:00Z", "15 de Enero de 2024"},
+		{"2024-01-15T23:59:59Z", "15 de Enero de 2024"},
+		{"2024-01-15T00:00:00-05:00", "15 de Enero de 2024"},
+		{"2024-12-31", "31 de Diciembre de 2024"},
+		{"15/01/2024", "15/01/2024"},
+	}
+	for _, tt := range tests {
+		if got := FormatDateInSpanish(tt.date); got != tt.want {
+			t.Errorf("FormatDateInSpanish(%q) = %q, se esperaba %q", tt.date, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package helpers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoadAuthorizedInvoice obtiene la factura indicada en el parámetro id y
+// verifica que el usuario autenticado sea su dueño o tenga el rol ADMIN. Si
+// algo falla responde al cliente con el error correspondiente y devuelve false.
+func LoadAuthorizedInvoice(c *gin.Context, invoices interfaces.InvoiceRepository, forbiddenMessage string) (models.Invoice, bool) {
+	// Get the user role from the JWT token
+	claims := c.MustGet("claims").(*models.Claims)
+	role := claims.Role
+	userID := claims.UserID
+
+	// Check if the user has the necessary role to access the route
+	if !VerifyRole(role, []string{common.ADMIN, common.USER}) {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, "No tienes permiso para acceder a esta página."))
+		return models.Invoice{}, false
+	}
+
+	// Validate the value of the id parameter
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.ErrorResponseInit(common.ErrInvalidID, "El valor del parámetro id debe ser un número entero positivo"))
+		return models.Invoice{}, false
+	}
+
+	invoice, err := invoices.GetByID(id)
+	if err == sql.ErrNoRows {
+		c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponseInit(common.ErrInvoiceNotFound, "No se encontró la factura con el ID especificado."))
+		return invoice, false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al obtener la factura."))
+		return invoice, false
+	}
+
+	// Check if the user is the owner of the invoice or has the ADMIN role
+	if invoice.UserID != userID && role != common.ADMIN {
+		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrNoPermission, forbiddenMessage))
+		return invoice, false
+	}
+
+	return invoice, true
+}
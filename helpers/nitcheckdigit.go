@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// nitWeights son los pesos de la DIAN para el dígito de verificación; se
+// aplican desde el dígito menos significativo.
+var nitWeights = [...]int{3, 7, 13, 17, 19, 23, 29, 37, 41, 43, 47, 53, 59, 67, 71}
+
+// NITCheckDigit calcula el dígito de verificación de un NIT (solo los dígitos, sin el DV).
+func NITCheckDigit(nit string) (int, error) {
+	if nit == "" || len(nit) > len(nitWeights) {
+		return 0, fmt.Errorf("el NIT debe tener entre 1 y %d dígitos", len(nitWeights))
+	}
+	sum := 0
+	for i := 0; i < len(nit); i++ {
+		digit := nit[len(nit)-1-i]
+		if digit < '0' || digit > '9' {
+			return 0, fmt.Errorf("el NIT solo puede contener dígitos")
+		}
+		sum += int(digit-'0') * nitWeights[i]
+	}
+	if remainder := sum % 11; remainder > 1 {
+		return 11 - remainder, nil
+	}
+	return sum % 11, nil
+}
+
+// NormalizeNIT quita puntos y espacios y devuelve el NIT como "900123456-7".
+// Si se escribió sin dígito de verificación lo calcula; si lo trae, lo comprueba.
+func NormalizeNIT(nit string) (string, error) {
+	nit = strings.NewReplacer(".", "", " ", "", ",", "").Replace(nit)
+	number, checkDigit, hasCheckDigit := strings.Cut(nit, "-")
+	if len(number) < 5 {
+		return "", fmt.Errorf("el NIT debe tener al menos 5 dígitos")
+	}
+
+	expected, err := NITCheckDigit(number)
+	if err != nil {
+		return "", err
+	}
+	if hasCheckDigit && checkDigit != strconv.Itoa(expected) {
+		return "", fmt.Errorf("el dígito de verificación no corresponde; para %s es %d", number, expected)
+	}
+	return fmt.Sprintf("%s-%d", number, expected), nil
+}
@@ -0,0 +1,65 @@
+package helpers
+
+import "testing"
+
+func TestNITCheckDigit(t *testing.T) {
+	// NIT publicados con su dígito de verificación
+	tests := []struct {
+		nit  string
+		want int
+	}{
+		{"800197268", 4}, // DIAN
+		{"890903938", 8}, // Bancolombia
+		{"899999068", 1}, // Ecopetrol
+		{"860034313", 7}, // Davivienda
+		{"900123456", 8},
+	}
+	for _, tt := range tests {
+		got, err := NITCheckDigit(tt.nit)
+		if err != nil {
+			t.Errorf("NITCheckDigit(%q): %v", tt.nit, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NITCheckDigit(%q) = %d, se esperaba %d", tt.nit, got, tt.want)
+		}
+	}
+}
+
+func TestNITCheckDigitRejectsInvalidInput(t *testing.T) {
+	for _, nit := range []string{"", "90012345A", "9001234567890123"} {
+		if _, err := NITCheckDigit(nit); err == nil {
+			t.Errorf("NITCheckDigit(%q) no devolvió error", nit)
+		}
+	}
+}
+
+func TestNormalizeNIT(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"800197268", "800197268-4"},
+		{"800197268-4", "800197268-4"},
+		{"800.197.268-4", "800197268-4"},
+		{" 890 903 938 ", "890903938-8"},
+		{"899,999,068-1", "899999068-1"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeNIT(tt.input)
+		if err != nil {
+			t.Errorf("NormalizeNIT(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeNIT(%q) = %q, se esperaba %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeNITRejectsWrongCheckDigit(t *testing.T) {
+	for _, nit := range []string{"800197268-5", "890903938-0", "1234", "1234-5", "ABCDE"} {
+		if got, err := NormalizeNIT(nit); err == nil {
+			t.Errorf("NormalizeNIT(%q) = %q, se esperaba un error", nit, got)
+		}
+	}
+}
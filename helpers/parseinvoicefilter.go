@@ -0,0 +1,157 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseInvoiceFilter lee los parámetros de filtrado y ordenamiento del listado de facturas:
+//
+//	fecha_desde, fecha_hasta   rango de fechas (AAAA-MM-DD, ambos inclusive)
+//	nit_empresa                NIT exacto de la empresa, con o sin dígito de verificación
+//	nombre_empresa             texto contenido en el nombre de la empresa
+//	empresa_id                 empresa del directorio a la que se le facturó
+//	documento_operador         documento exacto del operador
+//	valor_min, valor_max       rango de valor_total
+//	q                          texto contenido en la descripción de algún servicio
+//	estado                     estado de la factura (borrador, emitida, enviada, pagada o anulada)
+//	usuario_id                 dueño de las facturas (solo lo respeta el administrador)
+//	sort, order                campos y direcciones separados por comas, p. ej. sort=fecha,id&order=desc,asc
+//
+// filter_field/filter_value se siguen aceptando para los campos equivalentes.
+// No llena Limit ni Offset, que dependen de la paginación de cada controlador.
+func ParseInvoiceFilter(c *gin.Context) (models.InvoiceFilter, error) {
+	var filter models.InvoiceFilter
+
+	filter.DateFrom = strings.TrimSpace(c.Query("fecha_desde"))
+	filter.DateTo = strings.TrimSpace(c.Query("fecha_hasta"))
+	filter.CompanyTIN = strings.TrimSpace(c.Query("nit_empresa"))
+	filter.CompanyName = strings.TrimSpace(c.Query("nombre_empresa"))
+	filter.OperatorDocument = strings.TrimSpace(c.Query("documento_operador"))
+	filter.ServiceText = strings.TrimSpace(c.Query("q"))
+	filter.Status = strings.ToLower(strings.TrimSpace(c.Query("estado")))
+
+	if field, value := c.Query("filter_field"), strings.TrimSpace(c.Query("filter_value")); field != "" && value != "" {
+		switch field {
+		case "nit_empresa":
+			filter.CompanyTIN = value
+		case "nombre_empresa":
+			filter.CompanyName = value
+		case "documento_operador":
+			filter.OperatorDocument = value
+		case "fecha":
+			filter.DateFrom, filter.DateTo = value, value
+		default:
+			return filter, models.ErrorResponseInit(common.ErrInvalidFilter, "El campo de filtro '"+field+"' no es válido. Usa nit_empresa, nombre_empresa, documento_operador o fecha.")
+		}
+	}
+
+	for _, date := range []struct{ param, value string }{{"fecha_desde", filter.DateFrom}, {"fecha_hasta", filter.DateTo}} {
+		if date.value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", date.value); err != nil {
+			return filter, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro '"+date.param+"' debe tener el formato AAAA-MM-DD.")
+		}
+	}
+
+	if filter.Status != "" && !models.IsInvoiceStatus(filter.Status) {
+		return filter, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro 'estado' debe ser uno de: "+strings.Join(models.InvoiceStatuses, ", ")+".")
+	}
+
+	// El NIT se compara sin puntos ni dígito de verificación
+	if filter.CompanyTIN != "" {
+		filter.CompanyTIN, _, _ = strings.Cut(strings.NewReplacer(".", "", " ", "").Replace(filter.CompanyTIN), "-")
+	}
+
+	var err error
+	if filter.MinTotal, err = parseOptionalAmount(c.Query("valor_min")); err != nil {
+		return filter, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro 'valor_min' debe ser un número.")
+	}
+	if filter.MaxTotal, err = parseOptionalAmount(c.Query("valor_max")); err != nil {
+		return filter, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro 'valor_max' debe ser un número.")
+	}
+
+	if userIDStr := c.Query("usuario_id"); userIDStr != "" {
+		filter.UserID, err = strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil || filter.UserID <= 0 {
+			return filter, models.ErrorResponseInit(common.ErrInvalidUserID, "El parámetro 'usuario_id' debe ser un número entero positivo.")
+		}
+	}
+
+	if companyIDStr := strings.TrimSpace(c.Query("empresa_id")); companyIDStr != "" {
+		filter.CompanyID, err = strconv.ParseInt(companyIDStr, 10, 64)
+		if err != nil || filter.CompanyID <= 0 {
+			return filter, models.ErrorResponseInit(common.ErrInvalidFilter, "El parámetro 'empresa_id' debe ser un número entero positivo.")
+		}
+	}
+
+	filter.Sort, err = parseInvoiceSort(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+func parseOptionalAmount(value string) (*models.Money, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := models.ParseMoney(value)
+	if err != nil {
+		return nil, err
+	}
+	return &amount, nil
+}
+
+func parseInvoiceSort(sortParam string, orderParam string) ([]models.InvoiceSort, error) {
+	if sortParam == "" {
+		return nil, nil
+	}
+
+	fields := strings.Split(sortParam, ",")
+	var orders []string
+	if orderParam != "" {
+		orders = strings.Split(orderParam, ",")
+	}
+	if len(orders) > len(fields) {
+		return nil, models.ErrorResponseInit(common.ErrInvalidSort, "El parámetro 'order' tiene más valores que 'sort'.")
+	}
+
+	sort := make([]models.InvoiceSort, 0, len(fields))
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		if !isInvoiceSortField(field) {
+			return nil, models.ErrorResponseInit(common.ErrInvalidSort, "No se puede ordenar por '"+field+"'. Usa "+strings.Join(models.InvoiceSortFields, ", ")+".")
+		}
+
+		descending := false
+		if i < len(orders) {
+			switch strings.ToLower(strings.TrimSpace(orders[i])) {
+			case "asc":
+			case "desc":
+				descending = true
+			default:
+				return nil, models.ErrorResponseInit(common.ErrInvalidSort, "El parámetro 'order' solo admite asc o desc.")
+			}
+		}
+		sort = append(sort, models.InvoiceSort{Field: field, Descending: descending})
+	}
+	return sort, nil
+}
+
+func isInvoiceSortField(field string) bool {
+	for _, sortField := range models.InvoiceSortFields {
+		if field == sortField {
+			return true
+		}
+	}
+	return false
+}
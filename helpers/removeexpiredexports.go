@@ -0,0 +1,24 @@
+package helpers
+
+import (
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"log"
+	"os"
+)
+
+// RemoveExpiredExports elimina las exportaciones terminadas hace más de
+// models.ExportTTL junto con sus archivos. Los errores solo se registran: la
+// limpieza se vuelve a intentar en la siguiente pasada.
+func RemoveExpiredExports(exports interfaces.ExportRepository) {
+	files, err := exports.DeleteFinishedOlderThan(models.ExportTTL)
+	if err != nil {
+		log.Printf("exportaciones vencidas: %v", err)
+		return
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			log.Printf("exportaciones vencidas: %v", err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package helpers
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+)
+
+// ResolveInvoiceCompany completa la empresa de una factura a partir del
+// directorio de empresas de su dueño. Si la factura trae el id de la empresa
+// copia sus datos; si trae los datos de la empresa los valida y, si el dueño
+// ya tiene una empresa con ese NIT, copia los datos registrados. Si la
+// empresa no existe deja el id en 0 para que se registre una vez validada la
+// factura completa.
+func ResolveInvoiceCompany(invoice *models.Invoice, companies interfaces.CompanyRepository, ownerID int64) error {
+	if invoice.Company.ID != 0 {
+		company, err := companies.GetByID(invoice.Company.ID)
+		if err == sql.ErrNoRows || (err == nil && company.UserID != ownerID) {
+			return models.ErrorResponseInit(common.ErrCompanyNotFound, "No se encontró la empresa con el ID especificado.")
+		}
+		if err != nil {
+			return err
+		}
+		invoice.Company = company
+		invoice.Company.UserID = 0
+		return nil
+	}
+
+	if err := ValidateCompany(&invoice.Company, "empresa."); err != nil {
+		return err
+	}
+	company, err := companies.FindByTIN(ownerID, invoice.Company.TIN)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	invoice.Company = company
+	invoice.Company.UserID = 0
+	return nil
+}
@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"facturaexpress/taxes"
+	"strings"
+)
+
+// ValidateCatalogItem limpia los datos de un servicio del catálogo y verifica
+// que su tarifa de IVA exista en las tarifas vigentes; sin tarifa queda
+// exento. Devuelve un error INVALID_CATALOG_ITEM con el detalle de cada campo inválido.
+func ValidateCatalogItem(item *models.CatalogItem, rates taxes.Rates) error {
+	var details []models.FieldError
+	item.Description = strings.Join(strings.Fields(item.Description), " ")
+	item.Unit = strings.TrimSpace(item.Unit)
+	item.IVARate = strings.ToLower(strings.TrimSpace(item.IVARate))
+	if item.IVARate == "" {
+		item.IVARate = taxes.IVAExempt
+	}
+
+	if item.Description == "" {
+		details = append(details, models.FieldError{Field: "descripcion", Message: "Es obligatoria."})
+	}
+	if item.UnitPrice < 0 {
+		details = append(details, models.FieldError{Field: "valor_unitario", Message: "No puede ser negativo."})
+	}
+	if _, ok := rates.IVA[item.IVARate]; !ok {
+		details = append(details, models.FieldError{Field: "tarifa_iva", Message: "No es una tarifa de IVA válida. Usa 19, 5 o exento."})
+	}
+
+	if len(details) > 0 {
+		return models.ValidationErrorInit(common.ErrInvalidCatalogItem, "Revisa los datos del servicio.", details)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// phonePattern admite dígitos con espacios, guiones, paréntesis y un + inicial.
+var phonePattern = regexp.MustCompile(`^\+?[0-9 ()-]{7,20}$`)
+
+// ValidateCompany limpia los datos de una empresa y normaliza su NIT con el
+// dígito de verificación. fieldPrefix antecede el nombre de los campos en el
+// detalle del error ("empresa." cuando la empresa viene dentro de una
+// factura). Devuelve un error INVALID_COMPANY con el detalle de cada campo inválido.
+func ValidateCompany(company *models.Company, fieldPrefix string) error {
+	var details []models.FieldError
+	company.Name = strings.Join(strings.Fields(company.Name), " ")
+	company.Address = strings.TrimSpace(company.Address)
+	company.City = strings.TrimSpace(company.City)
+	company.Email = strings.ToLower(strings.TrimSpace(company.Email))
+	company.Phone = strings.TrimSpace(company.Phone)
+
+	if company.Name == "" {
+		details = append(details, models.FieldError{Field: fieldPrefix + "nombre", Message: "Es obligatorio."})
+	}
+	if nit, err := NormalizeNIT(company.TIN); err != nil {
+		details = append(details, models.FieldError{Field: fieldPrefix + "nit", Message: capitalize(err.Error()) + "."})
+	} else {
+		company.TIN = nit
+	}
+	if company.Email != "" {
+		if address, err := mail.ParseAddress(company.Email); err != nil || address.Address != company.Email {
+			details = append(details, models.FieldError{Field: fieldPrefix + "correo", Message: "No es un correo electrónico válido."})
+		}
+	}
+	if company.Phone != "" && !phonePattern.MatchString(company.Phone) {
+		details = append(details, models.FieldError{Field: fieldPrefix + "telefono", Message: "Debe tener entre 7 y 20 dígitos, espacios o guiones."})
+	}
+
+	if len(details) > 0 {
+		return models.ValidationErrorInit(common.ErrInvalidCompany, "Revisa los datos de la empresa.", details)
+	}
+	return nil
+}
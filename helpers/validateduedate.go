@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"strings"
+	"time"
+)
+
+// ValidateDueDate valida la fecha de vencimiento opcional de la factura: debe
+// tener el formato AAAA-MM-DD y no puede ser anterior a la fecha de la factura.
+func ValidateDueDate(invoice *models.Invoice) error {
+	invoice.DueDate = strings.TrimSpace(invoice.DueDate)
+	if invoice.DueDate == "" {
+		return nil
+	}
+
+	dueDate, err := time.Parse(models.NumberingDateLayout, invoice.DueDate)
+	if err != nil {
+		return models.ValidationErrorInit(common.ErrInvalidData, "La fecha de vencimiento no es válida.", []models.FieldError{
+			{Field: "fecha_vencimiento", Message: "Debe tener el formato AAAA-MM-DD."},
+		})
+	}
+	// La fecha de la factura puede traer hora; basta con el día
+	if len(invoice.Date) >= 10 {
+		if date, err := time.Parse(models.NumberingDateLayout, invoice.Date[:10]); err == nil && dueDate.Before(date) {
+			return models.ValidationErrorInit(common.ErrInvalidData, "La fecha de vencimiento no es válida.", []models.FieldError{
+				{Field: "fecha_vencimiento", Message: "No puede ser anterior a la fecha de la factura."},
+			})
+		}
+	}
+	return nil
+}
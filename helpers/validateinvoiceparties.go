@@ -0,0 +1,84 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// documentPatterns son las reglas de formato de cada tipo de documento, ya sin puntos ni espacios.
+var documentPatterns = map[string]*regexp.Regexp{
+	models.DocumentTypeCC:  regexp.MustCompile(`^[1-9][0-9]{2,9}$`),
+	models.DocumentTypeCE:  regexp.MustCompile(`^[0-9]{3,10}$`),
+	models.DocumentTypePAS: regexp.MustCompile(`^[A-Z0-9]{4,20}$`),
+	models.DocumentTypeTI:  regexp.MustCompile(`^[0-9]{10,11}$`),
+}
+
+var documentRules = map[string]string{
+	models.DocumentTypeCC:  "la cédula de ciudadanía debe tener entre 3 y 10 dígitos y no empezar por 0",
+	models.DocumentTypeCE:  "la cédula de extranjería debe tener entre 3 y 10 dígitos",
+	models.DocumentTypePAS: "el pasaporte debe tener entre 4 y 20 letras o dígitos",
+	models.DocumentTypeTI:  "la tarjeta de identidad debe tener 10 u 11 dígitos",
+}
+
+// ValidateInvoiceParties valida y normaliza la identificación de la empresa y
+// del operador: el NIT de la empresa queda con su dígito de verificación
+// ("900123456-7"), el tipo de documento del operador queda en el enum y el
+// documento sin puntos ni espacios. Devuelve un error INVALID_DOCUMENT con el
+// detalle de cada campo inválido.
+func ValidateInvoiceParties(invoice *models.Invoice) error {
+	var details []models.FieldError
+
+	if nit, err := NormalizeNIT(invoice.Company.TIN); err != nil {
+		details = append(details, models.FieldError{Field: "empresa.nit", Message: capitalize(err.Error()) + "."})
+	} else {
+		invoice.Company.TIN = nit
+	}
+
+	operator := &invoice.Operator
+	documentType, ok := models.NormalizeDocumentType(operator.DocumentType)
+	if !ok {
+		details = append(details, models.FieldError{
+			Field:   "operador.tipo_documento",
+			Message: fmt.Sprintf("El tipo de documento '%s' no es válido. Usa %s.", operator.DocumentType, strings.Join(models.DocumentTypes, ", ")),
+		})
+	} else {
+		operator.DocumentType = documentType
+		if message := normalizeDocument(documentType, &operator.Document); message != "" {
+			details = append(details, models.FieldError{Field: "operador.documento", Message: message})
+		}
+	}
+
+	if len(details) > 0 {
+		return models.ValidationErrorInit(common.ErrInvalidDocument, "Revisa los documentos de identificación de la factura.", details)
+	}
+	return nil
+}
+
+// normalizeDocument limpia el documento según su tipo y devuelve el mensaje de error, o "" si es válido.
+func normalizeDocument(documentType string, document *string) string {
+	if documentType == models.DocumentTypeNIT {
+		nit, err := NormalizeNIT(*document)
+		if err != nil {
+			return capitalize(err.Error()) + "."
+		}
+		*document = nit
+		return ""
+	}
+
+	cleaned := strings.ToUpper(strings.NewReplacer(".", "", " ", "", ",", "", "-", "").Replace(*document))
+	if !documentPatterns[documentType].MatchString(cleaned) {
+		return capitalize(documentRules[documentType]) + "."
+	}
+	*document = cleaned
+	return ""
+}
+
+func capitalize(message string) string {
+	if message == "" {
+		return message
+	}
+	return strings.ToUpper(message[:1]) + message[1:]
+}
@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"errors"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"testing"
+)
+
+// partiesInvoice es una factura con la empresa y el operador dados.
+func partiesInvoice(tin, documentType, document string) *models.Invoice {
+	return &models.Invoice{
+		Company:  models.Company{TIN: tin},
+		Operator: models.Operator{DocumentType: documentType, Document: document},
+	}
+}
+
+func TestValidateInvoicePartiesNormalizes(t *testing.T) {
+	tests := []struct {
+		documentType, document string
+		wantType, wantDocument string
+	}{
+		{"CC", "1.020.304.050", "CC", "1020304050"},
+		{"C.C.", "1020304050", "CC", "1020304050"},
+		{"Cédula de ciudadanía", "102 030", "CC", "102030"},
+		{"ce", "0123456", "CE", "0123456"},
+		{"Cédula de extranjería", "123", "CE", "123"},
+		{"Pasaporte", "ab-123456", "PAS", "AB123456"},
+		{"PA", "AB12", "PAS", "AB12"},
+		{"T.I.", "1002003004", "TI", "1002003004"},
+		{"Tarjeta de identidad", "10020030045", "TI", "10020030045"},
+		{"nit", "800.197.268", "NIT", "800197268-4"},
+	}
+	for _, tt := range tests {
+		invoice := partiesInvoice("890903938", tt.documentType, tt.document)
+		if err := ValidateInvoiceParties(invoice); err != nil {
+			t.Errorf("ValidateInvoiceParties(%q, %q): %v", tt.documentType, tt.document, err)
+			continue
+		}
+		if invoice.Operator.DocumentType != tt.wantType || invoice.Operator.Document != tt.wantDocument {
+			t.Errorf("ValidateInvoiceParties(%q, %q) dejó %q %q, se esperaba %q %q", tt.documentType, tt.document, invoice.Operator.DocumentType, invoice.Operator.Document, tt.wantType, tt.wantDocument)
+		}
+		if invoice.Company.TIN != "890903938-8" {
+			t.Errorf("el NIT de la empresa quedó %q, se esperaba 890903938-8", invoice.Company.TIN)
+		}
+	}
+}
+
+func TestValidateInvoicePartiesRejects(t *testing.T) {
+	tests := []struct {
+		name                        string
+		tin, documentType, document string
+		wantFields                  []string
+	}{
+		{"dígito de verificación", "890903938-1", "CC", "1020304050", []string{"empresa.nit"}},
+		{"NIT corto", "1234", "CC", "1020304050", []string{"empresa.nit"}},
+		{"tipo desconocido", "890903938", "RUT", "1020304050", []string{"operador.tipo_documento"}},
+		{"CC que empieza por 0", "890903938", "CC", "0123456", []string{"operador.documento"}},
+		{"CC muy larga", "890903938", "CC", "12345678901", []string{"operador.documento"}},
+		{"CE con letras", "890903938", "CE", "12A456", []string{"operador.documento"}},
+		{"pasaporte corto", "890903938", "PAS", "AB1", []string{"operador.documento"}},
+		{"TI corta", "890903938", "TI", "123456789", []string{"operador.documento"}},
+		{"NIT del operador", "890903938", "NIT", "800197268-5", []string{"operador.documento"}},
+		{"ambos", "890903938-1", "CC", "12", []string{"empresa.nit", "operador.documento"}},
+	}
+	for _, tt := range tests {
+		err := ValidateInvoiceParties(partiesInvoice(tt.tin, tt.documentType, tt.document))
+		var errorJson *models.ErrorJson
+		if !errors.As(err, &errorJson) {
+			t.Errorf("%s: se esperaba un *models.ErrorJson, se obtuvo %v", tt.name, err)
+			continue
+		}
+		if errorJson.Title != common.ErrInvalidDocument {
+			t.Errorf("%s: título %q, se esperaba %q", tt.name, errorJson.Title, common.ErrInvalidDocument)
+		}
+		if len(errorJson.Details) != len(tt.wantFields) {
+			t.Errorf("%s: detalles %v, se esperaban los campos %v", tt.name, errorJson.Details, tt.wantFields)
+			continue
+		}
+		for i, field := range tt.wantFields {
+			if errorJson.Details[i].Field != field {
+				t.Errorf("%s: campo %q, se esperaba %q", tt.name, errorJson.Details[i].Field, field)
+			}
+		}
+	}
+}
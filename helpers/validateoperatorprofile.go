@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"fmt"
+	"strings"
+)
+
+// ValidateOperatorProfile limpia y valida el perfil de operador. El documento
+// se normaliza con las mismas reglas que el de las facturas. Cada cuenta
+// necesita número y banco, los números no se repiten y a lo sumo una cuenta
+// es la predeterminada; si ninguna lo es, la primera pasa a serlo. Devuelve
+// un error INVALID_PROFILE con el detalle de cada campo inválido.
+func ValidateOperatorProfile(profile *models.OperatorProfile) error {
+	var details []models.FieldError
+	profile.Name = strings.Join(strings.Fields(profile.Name), " ")
+	profile.DocumentIssuanceCity = strings.TrimSpace(profile.DocumentIssuanceCity)
+	profile.Cellphone = strings.TrimSpace(profile.Cellphone)
+
+	if profile.DocumentType != "" || profile.Document != "" {
+		documentType, ok := models.NormalizeDocumentType(profile.DocumentType)
+		if !ok {
+			details = append(details, models.FieldError{
+				Field:   "tipo_documento",
+				Message: fmt.Sprintf("El tipo de documento '%s' no es válido. Usa %s.", profile.DocumentType, strings.Join(models.DocumentTypes, ", ")),
+			})
+		} else {
+			profile.DocumentType = documentType
+			if message := normalizeDocument(documentType, &profile.Document); message != "" {
+				details = append(details, models.FieldError{Field: "documento", Message: message})
+			}
+		}
+	}
+
+	if profile.BankAccounts == nil {
+		profile.BankAccounts = []models.BankAccount{}
+	}
+	seen := map[string]bool{}
+	defaults := 0
+	for i := range profile.BankAccounts {
+		account := &profile.BankAccounts[i]
+		field := fmt.Sprintf("cuentas_bancarias[%d]", i)
+		account.Number = strings.NewReplacer(" ", "", "-", "").Replace(account.Number)
+		account.Type = strings.ToLower(strings.TrimSpace(account.Type))
+		account.Bank = strings.TrimSpace(account.Bank)
+
+		if account.Number == "" {
+			details = append(details, models.FieldError{Field: field + ".numero", Message: "Es obligatorio."})
+		} else if seen[account.Number] {
+			details = append(details, models.FieldError{Field: field + ".numero", Message: "La cuenta está repetida."})
+		}
+		seen[account.Number] = true
+		if account.Bank == "" {
+			details = append(details, models.FieldError{Field: field + ".banco", Message: "Es obligatorio."})
+		}
+		if account.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		details = append(details, models.FieldError{Field: "cuentas_bancarias", Message: "Solo una cuenta puede ser la predeterminada."})
+	}
+	if defaults == 0 && len(profile.BankAccounts) > 0 {
+		profile.BankAccounts[0].Default = true
+	}
+
+	if len(details) > 0 {
+		return models.ValidationErrorInit(common.ErrInvalidProfile, "Revisa los datos del perfil de operador.", details)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"bytes"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// MaxProfileImageSize es el tamaño máximo en bytes de un logo o una firma.
+const MaxProfileImageSize = 1 << 20
+
+// maxProfileImageSide es el máximo de píxeles por lado de una imagen del perfil.
+const maxProfileImageSide = 4000
+
+// ValidateProfileImage revisa que la imagen sea de un tipo conocido y que su
+// contenido sea un PNG o un JPEG de hasta MaxProfileImageSize bytes y
+// maxProfileImageSide píxeles por lado. El tipo de contenido se detecta del
+// propio archivo y los PNG que gofpdf no sabe dibujar (16 bits por canal o
+// entrelazados) se convierten a 8 bits. Devuelve un error
+// INVALID_PROFILE_IMAGE con el detalle del problema.
+func ValidateProfileImage(profileImage *models.ProfileImage) error {
+	invalid := func(field, message string) error {
+		return models.ValidationErrorInit(common.ErrInvalidProfileImage, "Revisa la imagen del perfil.", []models.FieldError{{Field: field, Message: message}})
+	}
+
+	if !isProfileImageKind(profileImage.Kind) {
+		return invalid("tipo", fmt.Sprintf("El tipo de imagen '%s' no es válido. Usa %s.", profileImage.Kind, strings.Join(models.ProfileImageKinds, ", ")))
+	}
+	if len(profileImage.Content) == 0 {
+		return invalid("imagen", "La imagen está vacía.")
+	}
+	if len(profileImage.Content) > MaxProfileImageSize {
+		return invalid("imagen", fmt.Sprintf("La imagen no puede superar %d KB.", MaxProfileImageSize>>10))
+	}
+
+	profileImage.ContentType = http.DetectContentType(profileImage.Content)
+	var config image.Config
+	var err error
+	switch profileImage.ContentType {
+	case "image/png":
+		config, err = png.DecodeConfig(bytes.NewReader(profileImage.Content))
+	case "image/jpeg":
+		config, err = jpeg.DecodeConfig(bytes.NewReader(profileImage.Content))
+	default:
+		return invalid("imagen", "La imagen debe ser PNG o JPEG.")
+	}
+	if err != nil {
+		return invalid("imagen", "El archivo no es una imagen válida: "+err.Error())
+	}
+	if config.Width > maxProfileImageSide || config.Height > maxProfileImageSide {
+		return invalid("imagen", fmt.Sprintf("La imagen no puede medir más de %d píxeles por lado.", maxProfileImageSide))
+	}
+
+	if drawableInPDF(profileImage) {
+		return nil
+	}
+	if profileImage.ContentType == "image/png" {
+		if converted, err := pngToNRGBA(profileImage.Content); err == nil && len(converted) <= MaxProfileImageSize {
+			profileImage.Content = converted
+			if drawableInPDF(profileImage) {
+				return nil
+			}
+		}
+	}
+	return invalid("imagen", "La imagen no se puede dibujar en el PDF. Guárdala de nuevo como PNG o JPEG estándar.")
+}
+
+// ProfileImagePDFType es el tipo de imagen de gofpdf que corresponde al tipo de contenido.
+func ProfileImagePDFType(contentType string) string {
+	if contentType == "image/jpeg" {
+		return "JPG"
+	}
+	return "PNG"
+}
+
+// drawableInPDF registra la imagen en un documento de prueba.
+func drawableInPDF(profileImage *models.ProfileImage) bool {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	options := gofpdf.ImageOptions{ImageType: ProfileImagePDFType(profileImage.ContentType)}
+	pdf.RegisterImageOptionsReader(profileImage.Kind, options, bytes.NewReader(profileImage.Content))
+	return pdf.Error() == nil
+}
+
+// pngToNRGBA vuelve a codificar un PNG con 8 bits por canal y sin entrelazado.
+func pngToNRGBA(content []byte) ([]byte, error) {
+	decoded, err := png.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	converted := image.NewNRGBA(decoded.Bounds())
+	draw.Draw(converted, converted.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, converted); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func isProfileImageKind(kind string) bool {
+	for _, candidate := range models.ProfileImageKinds {
+		if kind == candidate {
+			return true
+		}
+	}
+	return false
+}
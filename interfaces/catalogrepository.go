@@ -0,0 +1,18 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// CatalogRepository concentra el acceso al catálogo de servicios. GetByID
+// devuelve sql.ErrNoRows cuando el servicio no existe. La descripción es única
+// por usuario: Create y Update devuelven un *models.ErrorJson
+// CATALOG_ITEM_ALREADY_EXISTS si el usuario ya tiene otro servicio con ella.
+type CatalogRepository interface {
+	Create(item *models.CatalogItem) error
+	GetByID(id int64) (models.CatalogItem, error)
+	List(filter models.CatalogFilter) ([]models.CatalogItem, error)
+	Count(filter models.CatalogFilter) (int, error)
+	Update(item models.CatalogItem) (bool, error)
+	Delete(id int64) (bool, error)
+	// Usage devuelve los servicios del catálogo más facturados, de más a menos facturas.
+	Usage(filter models.CatalogUsageFilter) ([]models.CatalogUsage, error)
+}
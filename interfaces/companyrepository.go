@@ -0,0 +1,18 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// CompanyRepository concentra el acceso a la tabla empresas. Los métodos que
+// buscan una empresa devuelven sql.ErrNoRows cuando no existe. El NIT es único
+// por usuario: Create y Update devuelven un *models.ErrorJson
+// COMPANY_ALREADY_EXISTS si el usuario ya tiene otra empresa con ese NIT.
+type CompanyRepository interface {
+	Create(company *models.Company) error
+	GetByID(id int64) (models.Company, error)
+	// FindByTIN busca la empresa del usuario con ese NIT, con o sin dígito de verificación.
+	FindByTIN(userID int64, tin string) (models.Company, error)
+	List(filter models.CompanyFilter) ([]models.Company, error)
+	Count(filter models.CompanyFilter) (int, error)
+	Update(company models.Company) (bool, error)
+	Delete(id int64) (bool, error)
+}
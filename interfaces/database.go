@@ -2,10 +2,34 @@ package interfaces
 
 import "database/sql"
 
+// Database es el acceso a la base de datos que usan los repositorios y las
+// migraciones. Devuelve interfaces en lugar de los tipos de database/sql para
+// que en las pruebas se pueda reemplazar por un doble.
 type Database interface {
-	Prepare(query string) (*sql.Stmt, error)
-	Query(query string, args ...interface{}) (*sql.Rows, error)
+	Query(query string, args ...interface{}) (Rows, error)
 	Exec(query string, args ...interface{}) (sql.Result, error)
-	QueryRow(query string, args ...interface{}) *sql.Row
+	QueryRow(query string, args ...interface{}) Row
+	Begin() (Tx, error)
+}
+
+// Rows recorre el resultado de Query.
+type Rows interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
 	Close() error
 }
+
+// Row es el resultado de QueryRow; Scan devuelve sql.ErrNoRows si no hubo filas.
+type Row interface {
+	Scan(dest ...interface{}) error
+}
+
+// Tx es una transacción abierta con Begin.
+type Tx interface {
+	Query(query string, args ...interface{}) (Rows, error)
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) Row
+	Commit() error
+	Rollback() error
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"facturaexpress/models"
+	"time"
+)
+
+// ExportRepository concentra el acceso a las exportaciones de facturas.
+// GetByID devuelve sql.ErrNoRows cuando la exportación no existe.
+type ExportRepository interface {
+	Create(export *models.InvoiceExport) error
+	GetByID(id int64) (models.InvoiceExport, error)
+	// Start pasa la exportación a en_proceso.
+	Start(id int64) error
+	SetProgress(id int64, processed int) error
+	// Finish guarda el estado final, las facturas procesadas, el archivo y el error.
+	Finish(export *models.InvoiceExport) error
+	// FailInterrupted marca como fallidas las exportaciones que quedaron sin
+	// terminar, p. ej. porque el servidor se reinició mientras corrían.
+	FailInterrupted() (int64, error)
+	// DeleteFinishedOlderThan elimina las exportaciones que terminaron hace
+	// más de age y devuelve las rutas de sus archivos.
+	DeleteFinishedOlderThan(age time.Duration) ([]string, error)
+}
@@ -0,0 +1,31 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// InvoiceRepository concentra el acceso a la tabla facturas. Los métodos que
+// buscan una factura por ID devuelven sql.ErrNoRows cuando no existe.
+type InvoiceRepository interface {
+	// Create guarda la factura como borrador. Si la empresa no tiene id la
+	// registra en el directorio del dueño en la misma transacción; si ya hay
+	// una con ese NIT devuelve un *models.ErrorJson COMPANY_ALREADY_EXISTS.
+	Create(invoice *models.Invoice) error
+	// Issue emite un borrador asignándole el número con la numeración del
+	// dueño; stamp completa los datos que dependen del número. Si no hay
+	// numeración, está vencida, se agotó el rango o la transición no es válida
+	// devuelve un *models.ErrorJson.
+	Issue(id int64, event models.InvoiceEvent, stamp func(invoice *models.Invoice) error) (models.Invoice, error)
+	// ChangeStatus pasa la factura a event.ToStatus y registra el evento; si la
+	// transición no es válida devuelve un *models.ErrorJson.
+	ChangeStatus(id int64, event models.InvoiceEvent) (models.Invoice, error)
+	ListEvents(invoiceID int64) ([]models.InvoiceEvent, error)
+	GetByID(id int64) (models.Invoice, error)
+	GetOwnerID(id int64) (int64, error)
+	GetStatus(id int64) (string, error)
+	List(filter models.InvoiceFilter) ([]models.Invoice, error)
+	Count(filter models.InvoiceFilter) (int, error)
+	// Update y Delete solo afectan facturas en borrador. Update registra la
+	// empresa como Create.
+	Update(id int64, invoice models.Invoice) (bool, error)
+	// Delete elimina la factura; si ownerID es distinto de 0 solo la elimina si pertenece a ese usuario.
+	Delete(id int64, ownerID int64) (bool, error)
+}
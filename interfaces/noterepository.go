@@ -0,0 +1,14 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// NoteRepository concentra el acceso a la tabla notas. GetByID devuelve
+// sql.ErrNoRows cuando la nota no existe.
+type NoteRepository interface {
+	// Create numera y guarda la nota y actualiza los totales de notas de la
+	// factura en una sola transacción. Si la factura no admite notas o una
+	// nota crédito supera su saldo devuelve un *models.ErrorJson.
+	Create(note *models.Note) error
+	GetByID(id int64) (models.Note, error)
+	ListByInvoice(invoiceID int64) ([]models.Note, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// NumberingRepository concentra el acceso a la tabla numeraciones. Cada
+// usuario tiene a lo sumo una numeración vigente.
+type NumberingRepository interface {
+	// GetByUserID devuelve sql.ErrNoRows si el usuario no ha configurado su numeración.
+	GetByUserID(userID int64) (models.Numbering, error)
+	// Save crea o reemplaza la numeración del usuario en una transacción: fija
+	// el consecutivo actual según la numeración guardada y la valida. Devuelve
+	// un *models.ErrorJson si no es válida.
+	Save(numbering *models.Numbering) error
+}
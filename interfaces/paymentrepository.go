@@ -0,0 +1,18 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// PaymentRepository concentra el acceso al libro de pagos. GetByID devuelve
+// sql.ErrNoRows cuando el pago no existe.
+type PaymentRepository interface {
+	// Create registra el pago, actualiza el total pagado de la factura y la
+	// marca como pagada si el pago cubre su saldo, en una sola transacción. Si
+	// la factura no admite pagos o el pago supera el saldo devuelve un *models.ErrorJson.
+	Create(payment *models.Payment) error
+	// Reverse registra el movimiento que anula el pago indicado. reversal trae
+	// el motivo, la fecha y el usuario; el resto se copia del pago original.
+	// Si la factura estaba pagada y vuelve a tener saldo, recupera su estado anterior.
+	Reverse(paymentID int64, reversal *models.Payment) error
+	GetByID(id int64) (models.Payment, error)
+	ListByInvoice(invoiceID int64) ([]models.Payment, error)
+}
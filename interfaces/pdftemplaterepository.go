@@ -0,0 +1,20 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// PDFTemplateRepository concentra el acceso a las plantillas de PDF. Los
+// métodos que buscan una plantilla devuelven sql.ErrNoRows cuando no existe.
+// El nombre es único: Create y Update devuelven un *models.ErrorJson
+// PDF_TEMPLATE_ALREADY_EXISTS si ya hay otra plantilla con él. Marcar una
+// plantilla como predeterminada desmarca la anterior.
+type PDFTemplateRepository interface {
+	Create(template *models.PDFTemplate) error
+	GetByID(id int64) (models.PDFTemplate, error)
+	List() ([]models.PDFTemplate, error)
+	Update(template models.PDFTemplate) (bool, error)
+	Delete(id int64) (bool, error)
+	// ForUser devuelve la plantilla asignada al usuario o, si no tiene, la predeterminada.
+	ForUser(userID int64) (models.PDFTemplate, error)
+	// AssignToUser asigna la plantilla al usuario; templateID nil vuelve a la predeterminada.
+	AssignToUser(userID int64, templateID *int64) (bool, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// ProfileRepository concentra el acceso a los perfiles de operador, sus
+// cuentas bancarias y sus imágenes. GetImage devuelve sql.ErrNoRows cuando el
+// usuario no tiene una imagen de ese tipo.
+type ProfileRepository interface {
+	// GetByUserID devuelve un perfil vacío si el usuario aún no ha guardado el suyo.
+	GetByUserID(userID int64) (models.OperatorProfile, error)
+	// Save reemplaza el perfil y todas sus cuentas bancarias.
+	Save(profile *models.OperatorProfile) error
+	// Images devuelve las imágenes del usuario con su contenido.
+	Images(userID int64) ([]models.ProfileImage, error)
+	GetImage(userID int64, kind string) (models.ProfileImage, error)
+	// SaveImage crea o reemplaza la imagen de ese tipo.
+	SaveImage(image *models.ProfileImage) error
+	DeleteImage(userID int64, kind string) (bool, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// ReportRepository arma los informes que agregan varias facturas.
+type ReportRepository interface {
+	// Aging agrupa por NIT de empresa los saldos pendientes de las facturas
+	// emitidas, enviadas o pagadas con saldo, repartidos por antigüedad.
+	Aging(filter models.AgingFilter) ([]models.AgingRow, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// RoleRepository concentra el acceso a las tablas roles y user_roles.
+type RoleRepository interface {
+	List() ([]models.Role, error)
+	Exists(id int) (bool, error)
+	// GetIDByName devuelve sql.ErrNoRows si el rol no existe.
+	GetIDByName(name string) (int64, error)
+	UserHasRole(userID int, roleID int) (bool, error)
+	UserHasRoleName(userID int64, name string) (bool, error)
+	AssignToUser(userID int64, roleID int64) error
+	UpdateUserRole(userID int, roleID int) error
+}
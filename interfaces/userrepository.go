@@ -0,0 +1,21 @@
+package interfaces
+
+import "facturaexpress/models"
+
+// UserRepository concentra el acceso a las tablas usuarios y jwt_blacklist.
+// Los métodos que buscan un usuario devuelven sql.ErrNoRows cuando no existe.
+type UserRepository interface {
+	Create(username string, hashedPassword []byte, email string) (int64, error)
+	Exists(id int64) (bool, error)
+	ExistsByUsernameOrEmail(username string, email string) (bool, error)
+	// FindByEmail devuelve el usuario con su rol y el hash de la contraseña.
+	FindByEmail(email string) (models.User, error)
+	GetByID(id int64) (models.User, error)
+	List() ([]models.User, error)
+	Update(id int64, username string, hashedPassword []byte, email string) (bool, error)
+	Delete(id int64) (bool, error)
+	SaveToken(userID int64, token string) error
+	GetToken(userID int64) (string, error)
+	IsTokenBlacklisted(token string) (bool, error)
+	BlacklistToken(token string) error
+}
@@ -1,27 +1,183 @@
 package main
 
 import (
+	"facturaexpress/data"
+	"facturaexpress/helpers"
+	"facturaexpress/interfaces"
+	"facturaexpress/pdftemplate"
+	"facturaexpress/repositories"
 	"facturaexpress/routes"
+	"facturaexpress/taxes"
+	"facturaexpress/ubl"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// exportCleanupInterval es cada cuánto se borran las exportaciones vencidas.
+const exportCleanupInterval = time.Hour
+
 func main() {
 	err := godotenv.Load(".env")
 	if err != nil {
 		log.Fatalf("Error loading .env file")
 	}
 
-	jwtKey := []byte(os.Getenv("SECRET_KEY"))
-	expTimeStr := os.Getenv("EXP_TIME")
+	dbConfig, err := data.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Error en la configuración de la base de datos: %v", err)
+	}
+	db, err := data.NewPostgresAdapter(dbConfig)
+	if err != nil {
+		log.Fatalf("Error al conectar con la base de datos: %v", err)
+	}
+	defer db.Close()
+
+	// Subcomando: facturaexpress migrate up|down|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		// log.Fatal no ejecuta los defer, así que la conexión se cierra antes
+		err := runMigrate(db, os.Args[2:])
+		db.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Aplica las migraciones pendientes al iniciar si AUTO_MIGRATE=true
+	if os.Getenv("AUTO_MIGRATE") == "true" {
+		applied, err := data.MigrateUp(db)
+		if err != nil {
+			log.Fatalf("Error al aplicar las migraciones: %v", err)
+		}
+		for _, migration := range applied {
+			log.Printf("Migración aplicada: %04d_%s", migration.Version, migration.Name)
+		}
+	}
+
+	// Tarifas de impuestos: las generales, o las de TAX_RATES_FILE si se indica
+	taxRates := taxes.DefaultRates()
+	if ratesFile := os.Getenv("TAX_RATES_FILE"); ratesFile != "" {
+		taxRates, err = taxes.LoadRates(ratesFile)
+		if err != nil {
+			log.Fatalf("Error al cargar las tarifas de impuestos: %v", err)
+		}
+	}
+
+	// Caché de PDF generados: PDF_CACHE_MB megabytes, 32 por defecto; 0 la desactiva
+	pdfCacheMB := 32
+	if cacheSize := os.Getenv("PDF_CACHE_MB"); cacheSize != "" {
+		pdfCacheMB, err = strconv.Atoi(cacheSize)
+		if err != nil || pdfCacheMB < 0 {
+			log.Fatalf("PDF_CACHE_MB debe ser un número entero de megabytes: %q", cacheSize)
+		}
+	}
+
+	// Fuentes de las plantillas de PDF: se buscan en FONTS_DIR, la carpeta font por defecto
+	fontDir := os.Getenv("FONTS_DIR")
+	if fontDir == "" {
+		fontDir = "font"
+	}
+
+	// Exportaciones de PDF: los ZIP se guardan en EXPORT_DIR y las que quedaron
+	// a medias en una ejecución anterior ya no van a terminar
+	exportDir := os.Getenv("EXPORT_DIR")
+	if exportDir == "" {
+		exportDir = filepath.Join(os.TempDir(), "facturaexpress-exportaciones")
+	}
+	if err := os.MkdirAll(exportDir, 0o700); err != nil {
+		log.Fatalf("Error al crear el directorio de exportaciones: %v", err)
+	}
+	exports := repositories.NewPostgresExportRepository(db)
+	if interrupted, err := exports.FailInterrupted(); err != nil {
+		log.Printf("Error al revisar las exportaciones pendientes: %v", err)
+	} else if interrupted > 0 {
+		log.Printf("Exportaciones interrumpidas marcadas como fallidas: %d", interrupted)
+	}
+
+	// Las exportaciones vencidas se borran al iniciar y luego cada hora, con sus archivos
+	helpers.RemoveExpiredExports(exports)
+	go func() {
+		for range time.Tick(exportCleanupInterval) {
+			helpers.RemoveExpiredExports(exports)
+		}
+	}()
 
 	// Crea un nuevo enrutador Gin y configura las rutas y los controladores de ruta
-	router := routes.NewRouter(jwtKey, expTimeStr)
+	router := routes.NewRouter(routes.Dependencies{
+		Invoices:     repositories.NewPostgresInvoiceRepository(db),
+		Companies:    repositories.NewPostgresCompanyRepository(db),
+		Catalog:      repositories.NewPostgresCatalogRepository(db),
+		Numberings:   repositories.NewPostgresNumberingRepository(db),
+		Notes:        repositories.NewPostgresNoteRepository(db),
+		Payments:     repositories.NewPostgresPaymentRepository(db),
+		Reports:      repositories.NewPostgresReportRepository(db),
+		Users:        repositories.NewPostgresUserRepository(db),
+		Profiles:     repositories.NewPostgresProfileRepository(db),
+		PDFTemplates: repositories.NewPostgresPDFTemplateRepository(db),
+		PDFCache:     pdftemplate.NewCache(int64(pdfCacheMB) << 20),
+		FontDir:      fontDir,
+		Exports:      exports,
+		ExportDir:    exportDir,
+		Roles:        repositories.NewPostgresRoleRepository(db),
+		JWTKey:       []byte(os.Getenv("SECRET_KEY")),
+		ExpTimeStr:   os.Getenv("EXP_TIME"),
+		TaxRates:     taxRates,
+		DIAN:         ubl.SettingsFromEnv(),
+	})
 
 	// Inicia el servidor Gin y escucha las solicitudes entrantes
 	addr := os.Getenv("SERVER_ADDRESS")
 	port := os.Getenv("SERVER_PORT")
 	router.Run(addr + ":" + port)
 }
+
+func runMigrate(db interfaces.Database, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("uso: %s migrate up|down|status", os.Args[0])
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := data.MigrateUp(db)
+		for _, migration := range applied {
+			fmt.Printf("Aplicada %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("error al aplicar las migraciones: %w", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("No hay migraciones pendientes")
+		}
+	case "down":
+		reverted, err := data.MigrateDown(db)
+		if err != nil {
+			return fmt.Errorf("error al revertir la migración: %w", err)
+		}
+		if reverted == nil {
+			fmt.Println("No hay migraciones para revertir")
+			return nil
+		}
+		fmt.Printf("Revertida %04d_%s\n", reverted.Version, reverted.Name)
+	case "status":
+		status, err := data.GetMigrationStatus(db)
+		if err != nil {
+			return fmt.Errorf("error al consultar el estado de las migraciones: %w", err)
+		}
+		for _, migration := range status {
+			state := "pendiente"
+			if migration.Applied {
+				state = "aplicada " + migration.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", migration.Version, migration.Name, state)
+		}
+	default:
+		return fmt.Errorf("subcomando desconocido %q. Uso: %s migrate up|down|status", args[0], os.Args[0])
+	}
+	return nil
+}
@@ -1,10 +1,9 @@
 package middleware
 
 import (
-	"database/sql"
 	"facturaexpress/common"
-	"facturaexpress/data"
 	"facturaexpress/helpers"
+	"facturaexpress/interfaces"
 	"facturaexpress/models"
 	"net/http"
 	"strings"
@@ -12,7 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(c *gin.Context, jwtKey []byte) {
+func AuthMiddleware(c *gin.Context, users interfaces.UserRepository, jwtKey []byte) {
 	claims, errCode, err := helpers.VerifyToken(c, jwtKey)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponseInit(errCode, err.Error()))
@@ -20,24 +19,14 @@ func AuthMiddleware(c *gin.Context, jwtKey []byte) {
 		return
 	}
 
-	db := data.GetInstance()
-
 	tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
-	stmt, err := db.Prepare(`SELECT COUNT(*) FROM jwt_blacklist WHERE token = $1`)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al preparar la consulta"))
-		c.Abort()
-		return
-	}
-	defer stmt.Close()
-	var count int
-	err = stmt.QueryRow(tokenString).Scan(&count)
+	blacklisted, err := users.IsTokenBlacklisted(tokenString)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al verificar si el token está en la lista negra"))
 		c.Abort()
 		return
 	}
-	if count > 0 {
+	if blacklisted {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponseInit(common.ErrInvalidToken, "Token inválido. Verifica o solicita uno nuevo."))
 		c.Abort()
 		return
@@ -48,7 +37,7 @@ func AuthMiddleware(c *gin.Context, jwtKey []byte) {
 	c.Next()
 }
 
-func RoleAuthMiddleware(c *gin.Context, role string) {
+func RoleAuthMiddleware(c *gin.Context, roles interfaces.RoleRepository, role string) {
 	// Verificar si el usuario tiene el rol necesario para acceder a la ruta
 	claims := c.MustGet("claims").(*models.Claims)
 	userID := claims.UserID
@@ -59,25 +48,16 @@ func RoleAuthMiddleware(c *gin.Context, role string) {
 		return
 	}
 
-	db := data.GetInstance()
-
-	var userRole models.UserRole
-	stmt, err := db.Prepare(`SELECT user_id, role_id FROM user_roles JOIN roles ON user_roles.role_id = roles.id WHERE user_roles.user_id = $1 AND roles.name = $2`)
+	hasRole, err := roles.UserHasRoleName(userID, role)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al preparar la consulta"))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al verificar si el usuario tiene el rol necesario"))
 		c.Abort()
 		return
 	}
-	defer stmt.Close()
-	err = stmt.QueryRow(userID, role).Scan(&userRole.UserID, &userRole.RoleID)
-	if err == sql.ErrNoRows {
+	if !hasRole {
 		c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponseInit(common.ErrInsuficientRole, "No tiene el rol necesario para acceder a esta ruta"))
 		c.Abort()
 		return
-	} else if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponseInit(common.ErrDBError, "Error al verificar si el usuario tiene el rol necesario"))
-		c.Abort()
-		return
 	}
 	c.Next()
 }
@@ -0,0 +1,43 @@
+package models
+
+// CatalogItem es un servicio del catálogo de un usuario. Las líneas de una
+// factura pueden referenciarlo con catalogo_id para copiar su descripción,
+// unidad, valor unitario y tarifa de IVA.
+type CatalogItem struct {
+	ID          int64  `json:"id"`
+	UserID      int64  `json:"usuario_id,omitempty"`
+	Description string `json:"descripcion"`
+	Unit        string `json:"unidad"`
+	UnitPrice   Money  `json:"valor_unitario"`
+	IVARate     string `json:"tarifa_iva"`
+}
+
+// CatalogFilter describe qué servicios del catálogo listar. UserID en 0
+// significa servicios de todos los usuarios; Query busca en la descripción.
+type CatalogFilter struct {
+	UserID int64
+	Query  string
+	Limit  int
+	Offset int
+}
+
+// CatalogUsageFilter describe qué facturas se cuentan en las estadísticas de
+// uso del catálogo. Las fechas (AAAA-MM-DD) vacías no filtran.
+type CatalogUsageFilter struct {
+	UserID   int64
+	DateFrom string
+	DateTo   string
+	Limit    int
+}
+
+// CatalogUsage resume cuánto se ha facturado un servicio del catálogo en las
+// facturas emitidas, enviadas o pagadas.
+type CatalogUsage struct {
+	CatalogID   int64   `json:"catalogo_id"`
+	Description string  `json:"descripcion"`
+	Invoices    int     `json:"facturas"`
+	Lines       int     `json:"lineas"`
+	Quantity    float64 `json:"cantidad"`
+	Billed      Money   `json:"valor_facturado"`
+	LastBilled  string  `json:"ultima_factura"`
+}
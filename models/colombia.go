@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Bogota es la hora de Colombia, UTC-5 y sin horario de verano. Las fechas de
+// las facturas, las resoluciones de numeración y el XML de la DIAN se leen en
+// esta zona, sin importar la del servidor.
+var Bogota = time.FixedZone("COT", -5*60*60)
+
+// NowInBogota devuelve la hora actual en Colombia.
+func NowInBogota() time.Time {
+	return time.Now().In(Bogota)
+}
+
+// ParseInvoiceDate lee la fecha de una factura o una nota como hora de
+// Colombia. La columna fecha es un TIMESTAMP sin zona que guarda la hora de
+// Colombia, pero lib/pq la devuelve marcada como UTC ("2024-01-15T00:00:00Z"),
+// así que se toma la hora tal cual, sin convertirla. También acepta un día
+// solo ("2024-01-15"). Es la única lectura de esas fechas: la usan el JSON, el
+// PDF, el XML y el CUFE, que así muestran siempre el mismo día.
+func ParseInvoiceDate(date string) (time.Time, error) {
+	var parsed time.Time
+	var err error
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+		if len(date) < len(layout) {
+			err = fmt.Errorf("%q es muy corta", date)
+			continue
+		}
+		if parsed, err = time.ParseInLocation(layout, date[:len(layout)], Bogota); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, err
+}
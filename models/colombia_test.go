@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInvoiceDateKeepsColombianMidnight(t *testing.T) {
+	want := time.Date(2024, 1, 15, 0, 0, 0, 0, Bogota)
+	for _, date := range []string{"2024-01-15T00:00:00Z", "2024-01-15T00:00:00-05:00", "2024-01-15T00:00:00.000000Z", "2024-01-15"} {
+		got, err := ParseInvoiceDate(date)
+		if err != nil {
+			t.Errorf("ParseInvoiceDate(%q): %v", date, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("ParseInvoiceDate(%q) = %s, se esperaba %s", date, got, want)
+		}
+	}
+	for _, date := range []string{"", "2024-01", "15/01/2024"} {
+		if _, err := ParseInvoiceDate(date); err == nil {
+			t.Errorf("ParseInvoiceDate(%q) no devolvió error", date)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "strings"
+
+// Tipos de documento de identidad admitidos para el operador.
+const (
+	DocumentTypeCC  = "CC"
+	DocumentTypeCE  = "CE"
+	DocumentTypeNIT = "NIT"
+	DocumentTypePAS = "PAS"
+	DocumentTypeTI  = "TI"
+)
+
+// DocumentTypes enumera los tipos de documento válidos.
+var DocumentTypes = []string{DocumentTypeCC, DocumentTypeCE, DocumentTypeNIT, DocumentTypePAS, DocumentTypeTI}
+
+// documentTypeAliases traduce las formas en que suelen escribirse los tipos de
+// documento. Las claves están en mayúsculas, sin puntos, tildes ni espacios.
+var documentTypeAliases = map[string]string{
+	"CC":                  DocumentTypeCC,
+	"CEDULA":              DocumentTypeCC,
+	"CEDULADECIUDADANIA":  DocumentTypeCC,
+	"CE":                  DocumentTypeCE,
+	"CEDULADEEXTRANJERIA": DocumentTypeCE,
+	"NIT":                 DocumentTypeNIT,
+	"PAS":                 DocumentTypePAS,
+	"PA":                  DocumentTypePAS,
+	"PP":                  DocumentTypePAS,
+	"PASAPORTE":           DocumentTypePAS,
+	"TI":                  DocumentTypeTI,
+	"TARJETADEIDENTIDAD":  DocumentTypeTI,
+}
+
+// NormalizeDocumentType lleva un tipo de documento a su valor del enum
+// ("C.C." -> "CC", "Pasaporte" -> "PAS"). Devuelve false si no lo reconoce.
+func NormalizeDocumentType(documentType string) (string, bool) {
+	key := strings.ToUpper(documentType)
+	key = strings.NewReplacer(".", "", " ", "", "-", "", "_", "", "É", "E", "Í", "I", "é", "E", "í", "I").Replace(key)
+	normalized, ok := documentTypeAliases[key]
+	return normalized, ok
+}
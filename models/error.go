@@ -5,11 +5,18 @@ import (
 )
 
 type ErrorJson struct {
-	Title     string `json:"title"`
-	Timestamp string `json:"timestamp"`
-	Message   string `json:"message"`
-	Type      string `json:"type"`
-	Source    string `json:"source"`
+	Title     string       `json:"title"`
+	Timestamp string       `json:"timestamp"`
+	Message   string       `json:"message"`
+	Type      string       `json:"type"`
+	Source    string       `json:"source"`
+	Details   []FieldError `json:"details,omitempty"`
+}
+
+// FieldError describe un campo inválido de la solicitud, p. ej. "empresa.nit".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 func (e *ErrorJson) DefaultErrorInit(title, timestamp, message, errorType, source string) {
@@ -32,6 +39,14 @@ func ErrorResponseInit(title, message string) *ErrorJson {
 	return &newError
 }
 
+// ValidationErrorInit crea un error con el detalle de cada campo inválido.
+func ValidationErrorInit(title, message string, details []FieldError) *ErrorJson {
+	newError := ErrorResponseInit(title, message)
+	newError.Type = "validationError"
+	newError.Details = details
+	return newError
+}
+
 func (e *ErrorJson) Error() string {
 	return e.Message
 }
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Estados de una exportación de facturas.
+const (
+	ExportStatusPending = "pendiente"
+	ExportStatusRunning = "en_proceso"
+	ExportStatusDone    = "terminada"
+	ExportStatusFailed  = "fallida"
+)
+
+// ExportTTL es el tiempo que se conserva una exportación terminada y su ZIP.
+const ExportTTL = 24 * time.Hour
+
+// InvoiceExport es una exportación en segundo plano de los PDF de las
+// facturas que cumplen Filter, en un ZIP con un índice CSV. Total es el número
+// de facturas al crearla y Processed las que ya están en el archivo. File es
+// la ruta del ZIP en el servidor y no se expone.
+type InvoiceExport struct {
+	ID         int64         `json:"id"`
+	UserID     int64         `json:"usuario_id"`
+	Filter     InvoiceFilter `json:"-"`
+	Status     string        `json:"estado"`
+	Total      int           `json:"total"`
+	Processed  int           `json:"procesadas"`
+	Error      string        `json:"error,omitempty"`
+	File       string        `json:"-"`
+	CreatedAt  time.Time     `json:"creado_en"`
+	FinishedAt *time.Time    `json:"terminado_en,omitempty"`
+}
@@ -1,18 +1,105 @@
 package models
 
+import "time"
+
+// Invoice es una cuenta de cobro. Se crea como borrador; Number, el número
+// asignado con la numeración del emisor (prefijo y consecutivo), y CUFE, el
+// código único de facturación electrónica, se asignan al emitirla.
+//
+// Subtotal, los totales de impuestos y NetPayable los calcula el servidor;
+// TotalValue es Subtotal + TotalIVA y NetPayable es TotalValue menos las
+// retenciones. DueDate, opcional, tiene el formato AAAA-MM-DD. AmountInWords es NetPayable
+// escrito en letras; no se guarda, se calcula al leer o calcular la factura.
+//
+// TotalCredits y TotalDebits suman las notas crédito y débito emitidas sobre
+// la factura, y AdjustedTotal es NetPayable menos las notas crédito más las
+// notas débito: el valor efectivo que se le cobra al cliente. TotalPaid suma
+// el libro de pagos, Balance es lo que falta por pagar y PaymentStatus lo
+// resume; los tres últimos los calcula ComputeBalance.
+//
+// UpdatedAt es la fecha del último cambio guardado; no se expone en el JSON.
 type Invoice struct {
-	ID         int       `json:"id"`
-	Company    Company   `json:"empresa"`
-	Date       string    `json:"fecha"`
-	Services   []Service `json:"servicios"`
-	TotalValue float64   `json:"valor_total"`
-	Operator   Operator  `json:"operador"`
-	UserID     int64     `json:"usuario_id"`
+	ID                int           `json:"id"`
+	Number            string        `json:"numero"`
+	CUFE              string        `json:"cufe"`
+	Status            string        `json:"estado"`
+	Company           Company       `json:"empresa"`
+	Date              string        `json:"fecha"`
+	DueDate           string        `json:"fecha_vencimiento"`
+	Services          []Service     `json:"servicios"`
+	Withholdings      []Withholding `json:"retenciones"`
+	Taxes             []TaxLine     `json:"impuestos"`
+	Subtotal          Money         `json:"subtotal"`
+	TotalIVA          Money         `json:"total_iva"`
+	TotalValue        Money         `json:"valor_total"`
+	TotalWithholdings Money         `json:"total_retenciones"`
+	NetPayable        Money         `json:"valor_neto"`
+	AmountInWords     string        `json:"valor_en_letras"`
+	TotalCredits      Money         `json:"total_notas_credito"`
+	TotalDebits       Money         `json:"total_notas_debito"`
+	AdjustedTotal     Money         `json:"valor_ajustado"`
+	TotalPaid         Money         `json:"total_pagado"`
+	Balance           Money         `json:"saldo"`
+	PaymentStatus     string        `json:"estado_pago"`
+	Operator          Operator      `json:"operador"`
+	UserID            int64         `json:"usuario_id"`
+	UpdatedAt         time.Time     `json:"-"`
+}
+
+// ComputeBalance calcula el valor ajustado por las notas, el saldo y el
+// estado de pago a partir de los totales guardados.
+func (i *Invoice) ComputeBalance() {
+	i.AdjustedTotal = i.NetPayable - i.TotalCredits + i.TotalDebits
+	i.Balance = i.AdjustedTotal - i.TotalPaid
+	switch {
+	case i.TotalPaid > 0 && i.Balance <= 0:
+		i.PaymentStatus = PaymentStatusPaid
+	case i.TotalPaid > 0:
+		i.PaymentStatus = PaymentStatusPartial
+	default:
+		i.PaymentStatus = PaymentStatusPending
+	}
+}
+
+// ClearServerFields descarta los campos que solo asigna el servidor (número,
+// CUFE, estado, notas, pagos y saldo), para que lo que envíe el cliente al
+// crear o modificar una factura no llegue a los totales ni a la respuesta.
+func (i *Invoice) ClearServerFields() {
+	i.ID = 0
+	i.Number = ""
+	i.CUFE = ""
+	i.Status = ""
+	i.TotalCredits = 0
+	i.TotalDebits = 0
+	i.AdjustedTotal = 0
+	i.TotalPaid = 0
+	i.Balance = 0
+	i.PaymentStatus = ""
+	i.UserID = 0
 }
 
+// Company es una empresa cliente. Se administra como entidad propia de cada
+// usuario (tabla empresas) y, al crear o modificar una factura, sus datos se
+// copian en la factura para que un cambio posterior no altere las facturas
+// ya hechas. En una factura, ID es la empresa de la que se copiaron los datos.
 type Company struct {
-	Name string `json:"nombre"`
-	TIN  string `json:"nit"`
+	ID      int64  `json:"id,omitempty"`
+	Name    string `json:"nombre"`
+	TIN     string `json:"nit"`
+	Address string `json:"direccion"`
+	City    string `json:"ciudad"`
+	Email   string `json:"correo"`
+	Phone   string `json:"telefono"`
+	UserID  int64  `json:"usuario_id,omitempty"`
+}
+
+// CompanyFilter describe qué empresas listar. UserID en 0 significa empresas
+// de todos los usuarios. Query busca en el nombre y en el NIT.
+type CompanyFilter struct {
+	UserID int64
+	Query  string
+	Limit  int
+	Offset int
 }
 
 type Operator struct {
@@ -26,7 +113,65 @@ type Operator struct {
 	Bank                 string `json:"banco"`
 }
 
+// Service es una línea de la factura. Subtotal lo calcula el servidor como
+// Quantity * UnitPrice - Discount, e IVAAmount aplicando la tarifa IVARate
+// ("19", "5" o "exento") sobre el subtotal. CatalogID es el servicio del
+// catálogo del que se copiaron los datos, o 0 si la línea se escribió a mano.
 type Service struct {
+	CatalogID   int64   `json:"catalogo_id,omitempty"`
 	Description string  `json:"descripcion"`
-	Value       float64 `json:"valor"`
+	Quantity    float64 `json:"cantidad"`
+	Unit        string  `json:"unidad"`
+	UnitPrice   Money   `json:"valor_unitario"`
+	Discount    Money   `json:"descuento"`
+	Subtotal    Money   `json:"subtotal"`
+	IVARate     string  `json:"tarifa_iva"`
+	IVAAmount   Money   `json:"valor_iva"`
+}
+
+// Withholding es una retención que el cliente solicita practicar sobre la
+// factura: retención en la fuente por concepto, ReteIVA o ReteICA por
+// municipio. Rate, en porcentaje, reemplaza la tarifa configurada.
+type Withholding struct {
+	Type         string   `json:"tipo"`
+	Concept      string   `json:"concepto,omitempty"`
+	Municipality string   `json:"municipio,omitempty"`
+	Rate         *float64 `json:"tarifa,omitempty"`
 }
+
+// TaxLine es un renglón calculado del resumen de impuestos: un IVA por tarifa
+// o una retención. Rate está en porcentaje.
+type TaxLine struct {
+	Type        string  `json:"tipo"`
+	Description string  `json:"descripcion"`
+	Base        Money   `json:"base"`
+	Rate        float64 `json:"tarifa"`
+	Amount      Money   `json:"valor"`
+}
+
+// InvoiceFilter describe qué facturas listar. UserID en 0 significa facturas de todos los usuarios.
+// Los campos vacíos o nil no filtran.
+type InvoiceFilter struct {
+	UserID           int64
+	DateFrom         string
+	DateTo           string
+	CompanyTIN       string
+	CompanyName      string
+	OperatorDocument string
+	MinTotal         *Money
+	MaxTotal         *Money
+	ServiceText      string
+	CompanyID        int64
+	Status           string
+	Sort             []InvoiceSort
+	Limit            int
+	Offset           int
+}
+
+type InvoiceSort struct {
+	Field      string
+	Descending bool
+}
+
+// InvoiceSortFields son los campos por los que se puede ordenar el listado de facturas.
+var InvoiceSortFields = []string{"id", "fecha", "valor_total", "nombre_empresa", "nit_empresa"}
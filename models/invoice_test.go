@@ -0,0 +1,53 @@
+package models
+
+import "testing"
+
+func TestClearServerFieldsIgnoresClientBalance(t *testing.T) {
+	invoice := Invoice{
+		ID:            9,
+		Number:        "FE99",
+		CUFE:          "abc",
+		Status:        InvoiceStatusPaid,
+		NetPayable:    100000,
+		TotalCredits:  1000,
+		TotalDebits:   2000,
+		AdjustedTotal: 5,
+		TotalPaid:     100000,
+		Balance:       0,
+		PaymentStatus: PaymentStatusPaid,
+		UserID:        3,
+	}
+	invoice.ClearServerFields()
+	invoice.ComputeBalance()
+
+	if invoice.ID != 0 || invoice.Number != "" || invoice.CUFE != "" || invoice.Status != "" || invoice.UserID != 0 {
+		t.Errorf("quedaron campos del servidor: %+v", invoice)
+	}
+	if invoice.NetPayable != 100000 {
+		t.Errorf("se borró el valor neto: %s", invoice.NetPayable)
+	}
+	if invoice.TotalPaid != 0 || invoice.AdjustedTotal != 100000 || invoice.Balance != 100000 || invoice.PaymentStatus != PaymentStatusPending {
+		t.Errorf("pagado %s, ajustado %s, saldo %s, estado %s", invoice.TotalPaid, invoice.AdjustedTotal, invoice.Balance, invoice.PaymentStatus)
+	}
+}
+
+func TestComputeBalance(t *testing.T) {
+	tests := []struct {
+		paid        Money
+		wantBalance Money
+		wantStatus  string
+	}{
+		{0, 110000, PaymentStatusPending},
+		{50000, 60000, PaymentStatusPartial},
+		{110000, 0, PaymentStatusPaid},
+		{120000, -10000, PaymentStatusPaid},
+	}
+	for _, tt := range tests {
+		// Neto 100.000, nota crédito de 10.000 y nota débito de 20.000
+		invoice := Invoice{NetPayable: 100000, TotalCredits: 10000, TotalDebits: 20000, TotalPaid: tt.paid}
+		invoice.ComputeBalance()
+		if invoice.AdjustedTotal != 110000 || invoice.Balance != tt.wantBalance || invoice.PaymentStatus != tt.wantStatus {
+			t.Errorf("pagado %s: ajustado %s, saldo %s, estado %s", tt.paid, invoice.AdjustedTotal, invoice.Balance, invoice.PaymentStatus)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"facturaexpress/common"
+	"fmt"
+	"time"
+)
+
+// Estados del ciclo de vida de una factura.
+const (
+	InvoiceStatusDraft     = "borrador"
+	InvoiceStatusIssued    = "emitida"
+	InvoiceStatusSent      = "enviada"
+	InvoiceStatusPaid      = "pagada"
+	InvoiceStatusCancelled = "anulada"
+)
+
+// InvoiceStatuses enumera los estados válidos.
+var InvoiceStatuses = []string{InvoiceStatusDraft, InvoiceStatusIssued, InvoiceStatusSent, InvoiceStatusPaid, InvoiceStatusCancelled}
+
+// invoiceTransitions indica a qué estados se puede pasar desde cada estado.
+// Una factura anulada ya no cambia. Una pagada vuelve al estado que tenía
+// antes de pagarse si recupera saldo, por la reversa de un pago o una nota
+// débito. Las transiciones hacia y desde pagada las hace solo el servidor
+// según el saldo; las acciones del usuario usan CheckManualInvoiceTransition.
+var invoiceTransitions = map[string][]string{
+	InvoiceStatusDraft:  {InvoiceStatusIssued},
+	InvoiceStatusIssued: {InvoiceStatusSent, InvoiceStatusPaid, InvoiceStatusCancelled},
+	InvoiceStatusSent:   {InvoiceStatusPaid, InvoiceStatusCancelled},
+	InvoiceStatusPaid:   {InvoiceStatusIssued, InvoiceStatusSent},
+}
+
+// IsInvoiceStatus indica si el texto es un estado válido.
+func IsInvoiceStatus(status string) bool {
+	for _, valid := range InvoiceStatuses {
+		if status == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckInvoiceTransition devuelve un error INVALID_STATUS_TRANSITION si no se
+// puede pasar de un estado al otro.
+func CheckInvoiceTransition(from, to string) error {
+	for _, allowed := range invoiceTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return ErrorResponseInit(common.ErrInvalidStatusTransition, fmt.Sprintf("Una factura en estado '%s' no puede pasar a '%s'.", from, to))
+}
+
+// CheckManualInvoiceTransition es CheckInvoiceTransition para las acciones del
+// usuario (emitir, enviar y anular), que no pueden llevar una factura a pagada
+// ni sacarla de ese estado.
+func CheckManualInvoiceTransition(from, to string) error {
+	if from == InvoiceStatusPaid || to == InvoiceStatusPaid {
+		return ErrorResponseInit(common.ErrInvalidStatusTransition, fmt.Sprintf("Una factura en estado '%s' no puede pasar a '%s'; el estado pagada depende solo de los pagos.", from, to))
+	}
+	return CheckInvoiceTransition(from, to)
+}
+
+// InvoiceEvent registra un cambio de estado de una factura.
+type InvoiceEvent struct {
+	ID         int64     `json:"id"`
+	InvoiceID  int64     `json:"factura_id"`
+	FromStatus string    `json:"estado_anterior"`
+	ToStatus   string    `json:"estado_nuevo"`
+	Reason     string    `json:"motivo"`
+	UserID     int64     `json:"usuario_id"`
+	CreatedAt  time.Time `json:"creado_en"`
+}
+
+// StatusChangeRequest es el cuerpo de las acciones que cambian el estado de una factura.
+type StatusChangeRequest struct {
+	Reason string `json:"motivo"`
+}
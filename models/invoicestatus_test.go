@@ -0,0 +1,75 @@
+package models
+
+import (
+	"facturaexpress/common"
+	"testing"
+)
+
+func TestCheckInvoiceTransition(t *testing.T) {
+	allowed := map[[2]string]bool{
+		{InvoiceStatusDraft, InvoiceStatusIssued}:     true,
+		{InvoiceStatusIssued, InvoiceStatusSent}:      true,
+		{InvoiceStatusIssued, InvoiceStatusPaid}:      true,
+		{InvoiceStatusIssued, InvoiceStatusCancelled}: true,
+		{InvoiceStatusSent, InvoiceStatusPaid}:        true,
+		{InvoiceStatusSent, InvoiceStatusCancelled}:   true,
+		// Al recuperar saldo vuelve al estado anterior
+		{InvoiceStatusPaid, InvoiceStatusIssued}: true,
+		{InvoiceStatusPaid, InvoiceStatusSent}:   true,
+	}
+	// Se prueban todas las combinaciones, incluidos un estado desconocido y quedarse en el mismo
+	statuses := append(append([]string{}, InvoiceStatuses...), "desconocido")
+	for _, from := range statuses {
+		for _, to := range statuses {
+			err := CheckInvoiceTransition(from, to)
+			if allowed[[2]string{from, to}] {
+				if err != nil {
+					t.Errorf("%s -> %s: %v", from, to, err)
+				}
+				continue
+			}
+			if errJSON, ok := err.(*ErrorJson); !ok || errJSON.Title != common.ErrInvalidStatusTransition {
+				t.Errorf("%s -> %s: se esperaba %s, se obtuvo %v", from, to, common.ErrInvalidStatusTransition, err)
+			}
+		}
+	}
+}
+
+func TestCheckManualInvoiceTransition(t *testing.T) {
+	for _, pair := range [][2]string{
+		{InvoiceStatusDraft, InvoiceStatusIssued},
+		{InvoiceStatusIssued, InvoiceStatusSent},
+		{InvoiceStatusSent, InvoiceStatusCancelled},
+	} {
+		if err := CheckManualInvoiceTransition(pair[0], pair[1]); err != nil {
+			t.Errorf("%s -> %s: %v", pair[0], pair[1], err)
+		}
+	}
+	// Las transiciones de pagada las permite la tabla, pero solo las hace el servidor
+	for _, pair := range [][2]string{
+		{InvoiceStatusIssued, InvoiceStatusPaid},
+		{InvoiceStatusSent, InvoiceStatusPaid},
+		{InvoiceStatusPaid, InvoiceStatusIssued},
+		{InvoiceStatusPaid, InvoiceStatusSent},
+		{InvoiceStatusPaid, InvoiceStatusCancelled},
+		{InvoiceStatusCancelled, InvoiceStatusIssued},
+	} {
+		err := CheckManualInvoiceTransition(pair[0], pair[1])
+		if errJSON, ok := err.(*ErrorJson); !ok || errJSON.Title != common.ErrInvalidStatusTransition {
+			t.Errorf("%s -> %s: se esperaba %s, se obtuvo %v", pair[0], pair[1], common.ErrInvalidStatusTransition, err)
+		}
+	}
+}
+
+func TestIsInvoiceStatus(t *testing.T) {
+	for _, status := range InvoiceStatuses {
+		if !IsInvoiceStatus(status) {
+			t.Errorf("IsInvoiceStatus(%q) = false", status)
+		}
+	}
+	for _, status := range []string{"", "Emitida", "pendiente"} {
+		if IsInvoiceStatus(status) {
+			t.Errorf("IsInvoiceStatus(%q) = true", status)
+		}
+	}
+}
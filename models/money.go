@@ -0,0 +1,242 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"facturaexpress/common"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money representa un valor monetario en centavos. Todas las sumas y
+// multiplicaciones se hacen sobre enteros, así que los totales no acumulan los
+// errores de redondeo de float64. Se serializa en JSON como número con dos
+// decimales y en SQL como texto decimal compatible con NUMERIC.
+type Money int64
+
+// moneyScale es la cantidad de centavos por unidad.
+const moneyScale = 100
+
+// maxMoneyCents acota los montos en centavos, con margen para sumar dos sin
+// desbordar int64.
+const maxMoneyCents = 1 << 62
+
+// ErrMoneyOutOfRange indica que un monto no cabe en Money.
+var ErrMoneyOutOfRange = errors.New("valor monetario fuera de rango")
+
+// Currency define cómo se redondea y se presenta una moneda.
+type Currency struct {
+	Code string
+	// Decimals es el número de decimales que se conservan al redondear (0, 1 o 2).
+	Decimals           int
+	Symbol             string
+	ThousandsSeparator string
+	DecimalSeparator   string
+}
+
+var (
+	COP = Currency{Code: "COP", Decimals: 2, Symbol: "$", ThousandsSeparator: ".", DecimalSeparator: ","}
+	USD = Currency{Code: "USD", Decimals: 2, Symbol: "US$", ThousandsSeparator: ",", DecimalSeparator: "."}
+	EUR = Currency{Code: "EUR", Decimals: 2, Symbol: "€", ThousandsSeparator: ".", DecimalSeparator: ","}
+)
+
+// DefaultCurrency es la moneda de las facturas.
+var DefaultCurrency = COP
+
+// LookupCurrency busca una moneda soportada por su código ISO 4217.
+func LookupCurrency(code string) (Currency, bool) {
+	for _, currency := range []Currency{COP, USD, EUR} {
+		if strings.EqualFold(currency.Code, code) {
+			return currency, true
+		}
+	}
+	return Currency{}, false
+}
+
+// ParseMoney convierte un texto decimal ("1234", "1234.5", "-0.75", "1e6") a Money sin
+// pasar por float64. Si trae más de dos decimales se redondea al centavo, con
+// las mitades alejándose de cero.
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok || strings.Contains(value, "/") {
+		return 0, fmt.Errorf("valor monetario inválido: %q", value)
+	}
+	return moneyFromRat(rat)
+}
+
+// MoneyFromFloat convierte un float64 usando su representación decimal más corta.
+func MoneyFromFloat(value float64) (Money, error) {
+	return ParseMoney(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// AmountOutOfRange es el error de validación de un monto de la factura que no
+// cabe en Money, con field como el campo que lo produjo.
+func AmountOutOfRange(field string) *ErrorJson {
+	return ValidationErrorInit(common.ErrAmountOutOfRange, "Los montos de la factura superan el máximo admitido.", []FieldError{{Field: field, Message: "El valor es demasiado grande."}})
+}
+
+// Multiply multiplica por un factor decimal, como una cantidad o una tarifa, y
+// redondea el resultado al centavo. Devuelve ErrMoneyOutOfRange si el
+// resultado no cabe en Money.
+func (m Money) Multiply(factor float64) (Money, error) {
+	rat, ok := new(big.Rat).SetString(strconv.FormatFloat(factor, 'f', -1, 64))
+	if !ok {
+		return 0, ErrMoneyOutOfRange
+	}
+	return moneyFromRat(rat.Mul(rat, big.NewRat(int64(m), moneyScale)))
+}
+
+// Percent calcula el porcentaje indicado del valor (19 -> 19 %) y redondea al
+// centavo. Devuelve ErrMoneyOutOfRange si el resultado no cabe en Money.
+func (m Money) Percent(rate float64) (Money, error) {
+	rat, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+	if !ok {
+		return 0, ErrMoneyOutOfRange
+	}
+	return moneyFromRat(rat.Mul(rat, big.NewRat(int64(m), moneyScale*100)))
+}
+
+// Add suma dos montos. Devuelve ErrMoneyOutOfRange si la suma no cabe en Money.
+func (m Money) Add(other Money) (Money, error) {
+	// Los dos sumandos están por debajo de maxMoneyCents, así que la suma no desborda int64
+	sum := int64(m) + int64(other)
+	if sum >= maxMoneyCents || sum <= -maxMoneyCents {
+		return 0, ErrMoneyOutOfRange
+	}
+	return Money(sum), nil
+}
+
+// Round redondea según los decimales de la moneda, con las mitades alejándose de cero.
+func (m Money) Round(currency Currency) Money {
+	step := int64(1)
+	for i := currency.Decimals; i < 2; i++ {
+		step *= 10
+	}
+	return Money(roundHalfAwayFromZero(big.NewRat(int64(m), step)) * step)
+}
+
+// Format presenta el valor en la moneda predeterminada, p. ej. "1.234.567,89".
+func (m Money) Format() string {
+	return m.FormatIn(DefaultCurrency)
+}
+
+// FormatIn presenta el valor con los separadores y decimales de la moneda dada.
+// Es el único formateador de montos: lo usan el PDF y los mensajes de la API.
+func (m Money) FormatIn(currency Currency) string {
+	rounded := m.Round(currency)
+	negative := rounded < 0
+	if negative {
+		rounded = -rounded
+	}
+
+	integer := strconv.FormatInt(int64(rounded)/moneyScale, 10)
+	for i := len(integer) - 3; i > 0; i -= 3 {
+		integer = integer[:i] + currency.ThousandsSeparator + integer[i:]
+	}
+
+	formatted := integer
+	if currency.Decimals > 0 {
+		cents := fmt.Sprintf("%02d", int64(rounded)%moneyScale)
+		formatted += currency.DecimalSeparator + cents[:currency.Decimals]
+	}
+	if negative {
+		formatted = "-" + formatted
+	}
+	return formatted
+}
+
+// FormatWithSymbol antepone el símbolo de la moneda, p. ej. "$ 1.234.567,89".
+func (m Money) FormatWithSymbol(currency Currency) string {
+	return currency.Symbol + " " + m.FormatIn(currency)
+}
+
+// String devuelve el valor como decimal con punto y dos decimales, p. ej. "1234567.89".
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/moneyScale, value%moneyScale)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON acepta tanto números como textos decimales.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		var unquoted string
+		if err := json.Unmarshal(data, &unquoted); err != nil {
+			return err
+		}
+		text = unquoted
+	}
+	value, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = value
+	return nil
+}
+
+// Value guarda el monto como texto decimal, que PostgreSQL convierte a NUMERIC sin pérdida.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		parsed, err := ParseMoney(string(value))
+		*m = parsed
+		return err
+	case string:
+		parsed, err := ParseMoney(value)
+		*m = parsed
+		return err
+	case int64:
+		*m = Money(value * moneyScale)
+		return nil
+	case float64:
+		parsed, err := MoneyFromFloat(value)
+		*m = parsed
+		return err
+	default:
+		return fmt.Errorf("no se puede convertir %T a Money", src)
+	}
+}
+
+func moneyFromRat(rat *big.Rat) (Money, error) {
+	cents := new(big.Rat).Mul(rat, big.NewRat(moneyScale, 1))
+	if new(big.Int).Abs(new(big.Int).Quo(cents.Num(), cents.Denom())).Cmp(big.NewInt(maxMoneyCents)) >= 0 {
+		return 0, ErrMoneyOutOfRange
+	}
+	return Money(roundHalfAwayFromZero(cents)), nil
+}
+
+func roundHalfAwayFromZero(rat *big.Rat) int64 {
+	num := new(big.Int).Abs(rat.Num())
+	den := rat.Denom()
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(remainder, big.NewInt(2)).Cmp(den) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if rat.Sign() < 0 {
+		quotient.Neg(quotient)
+	}
+	return quotient.Int64()
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Money
+	}{
+		{"0", 0},
+		{"1234", 123400},
+		{"1234.5", 123450},
+		{" 1234.56 ", 123456},
+		{"-0.75", -75},
+		{"1e6", 100000000},
+		// Más de dos decimales se redondean al centavo, con las mitades alejándose de cero
+		{"0.005", 1},
+		{"0.0049", 0},
+		{"-0.005", -1},
+		{"2.675", 268},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.input)
+		if err != nil {
+			t.Errorf("ParseMoney(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, se esperaba %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoneyRejectsInvalidInput(t *testing.T) {
+	for _, input := range []string{"", "abc", "1/3", "1,5", "1e30"} {
+		if got, err := ParseMoney(input); err == nil {
+			t.Errorf("ParseMoney(%q) = %d, se esperaba un error", input, got)
+		}
+	}
+}
+
+func TestMoneyFromFloatAvoidsBinaryErrors(t *testing.T) {
+	// 0.1 + 0.2 en float64 es 0.30000000000000004
+	got, err := MoneyFromFloat(0.1 + 0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 30 {
+		t.Fatalf("MoneyFromFloat(0.1 + 0.2) = %d, se esperaba 30", got)
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	tests := []struct {
+		value    Money
+		decimals int
+		want     Money
+	}{
+		{123456, 2, 123456},
+		{123456, 1, 123460},
+		{123455, 1, 123460},
+		{123454, 1, 123450},
+		{123450, 0, 123500},
+		{123449, 0, 123400},
+		{-123450, 0, -123500},
+	}
+	for _, tt := range tests {
+		if got := tt.value.Round(Currency{Decimals: tt.decimals}); got != tt.want {
+			t.Errorf("Money(%d).Round(%d decimales) = %d, se esperaba %d", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMultiplyAndPercent(t *testing.T) {
+	tests := []struct {
+		name string
+		got  func() (Money, error)
+		want Money
+	}{
+		{"Money(333).Multiply(1.5)", func() (Money, error) { return Money(333).Multiply(1.5) }, 500},
+		{"Money(1000000).Percent(19)", func() (Money, error) { return Money(1000000).Percent(19) }, 190000},
+		// 0,4 % de 12.345,67 es 49,38268
+		{"Money(1234567).Percent(0.4)", func() (Money, error) { return Money(1234567).Percent(0.4) }, 4938},
+	}
+	for _, tt := range tests {
+		got, err := tt.got()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s = %d, se esperaba %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyOutOfRange(t *testing.T) {
+	huge := Money(maxMoneyCents - 1)
+	if _, err := huge.Multiply(2); err != ErrMoneyOutOfRange {
+		t.Errorf("Multiply fuera de rango: %v", err)
+	}
+	if _, err := Money(100).Multiply(math.Inf(1)); err != ErrMoneyOutOfRange {
+		t.Errorf("Multiply por infinito: %v", err)
+	}
+	if _, err := huge.Percent(101); err != ErrMoneyOutOfRange {
+		t.Errorf("Percent fuera de rango: %v", err)
+	}
+	if _, err := huge.Add(1); err != ErrMoneyOutOfRange {
+		t.Errorf("Add fuera de rango: %v", err)
+	}
+	if _, err := (-huge).Add(-1); err != ErrMoneyOutOfRange {
+		t.Errorf("Add negativo fuera de rango: %v", err)
+	}
+	if got, err := huge.Add(-huge); err != nil || got != 0 {
+		t.Errorf("huge.Add(-huge) = %d, %v", got, err)
+	}
+	if got, err := Money(150).Add(275); err != nil || got != 425 {
+		t.Errorf("Money(150).Add(275) = %d, %v", got, err)
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		value    Money
+		currency Currency
+		want     string
+	}{
+		{123456789, COP, "1.234.567,89"},
+		{-100, COP, "-1,00"},
+		{123456789, USD, "1,234,567.89"},
+	}
+	for _, tt := range tests {
+		if got := tt.value.FormatIn(tt.currency); got != tt.want {
+			t.Errorf("Money(%d).FormatIn(%s) = %q, se esperaba %q", tt.value, tt.currency.Code, got, tt.want)
+		}
+	}
+	if got := Money(-123456).String(); got != "-1234.56" {
+		t.Errorf("Money(-123456).String() = %q, se esperaba -1234.56", got)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var values []Money
+	if err := json.Unmarshal([]byte(`[12.5, "7.25", null]`), &values); err != nil {
+		t.Fatal(err)
+	}
+	if values[0] != 1250 || values[1] != 725 || values[2] != 0 {
+		t.Fatalf("json.Unmarshal = %v", values)
+	}
+	out, err := json.Marshal(Money(1250))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "12.50" {
+		t.Fatalf("json.Marshal(Money(1250)) = %s, se esperaba 12.50", out)
+	}
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"facturaexpress/common"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Tipos de nota que se pueden emitir sobre una factura.
+const (
+	NoteTypeCredit = "credito"
+	NoteTypeDebit  = "debito"
+)
+
+// notePrefixes son los prefijos con que se numeran las notas de cada tipo.
+var notePrefixes = map[string]string{
+	NoteTypeCredit: "NC",
+	NoteTypeDebit:  "ND",
+}
+
+// noteReasons son los códigos del concepto de corrección de la DIAN para cada
+// tipo de nota.
+var noteReasons = map[string]map[string]string{
+	NoteTypeCredit: {
+		"1": "Devolución parcial de los bienes y/o no aceptación parcial del servicio",
+		"2": "Anulación de factura electrónica",
+		"3": "Rebaja o descuento parcial o total",
+		"4": "Ajuste de precio",
+		"5": "Descuento comercial por pronto pago",
+		"6": "Descuento comercial por volumen de ventas",
+	},
+	NoteTypeDebit: {
+		"1": "Intereses",
+		"2": "Gastos por cobrar",
+		"3": "Cambio del valor",
+		"4": "Otros",
+	},
+}
+
+// Note es una nota crédito o débito que corrige una factura emitida. Tiene sus
+// propias líneas, su concepto de corrección y su propia numeración. Subtotal,
+// TotalIVA y Total los calcula el servidor a partir de las líneas; una nota
+// crédito resta Total del saldo de la factura y una nota débito lo suma.
+type Note struct {
+	ID                int64     `json:"id"`
+	InvoiceID         int64     `json:"factura_id"`
+	Type              string    `json:"tipo"`
+	Number            string    `json:"numero"`
+	ReasonCode        string    `json:"codigo_concepto"`
+	ReasonDescription string    `json:"concepto"`
+	Description       string    `json:"descripcion"`
+	Date              string    `json:"fecha"`
+	Services          []Service `json:"servicios"`
+	Taxes             []TaxLine `json:"impuestos"`
+	Subtotal          Money     `json:"subtotal"`
+	TotalIVA          Money     `json:"total_iva"`
+	Total             Money     `json:"total"`
+	AmountInWords     string    `json:"valor_en_letras"`
+	UserID            int64     `json:"usuario_id"`
+	CreatedAt         time.Time `json:"creado_en"`
+}
+
+// Validate normaliza el tipo y el código del concepto, completa la
+// descripción del concepto y la fecha (hoy si no viene) y devuelve un error
+// INVALID_NOTE con el detalle de cada campo inválido.
+func (n *Note) Validate(today time.Time) error {
+	var details []FieldError
+	n.Type = strings.ToLower(strings.TrimSpace(n.Type))
+	n.ReasonCode = strings.TrimSpace(n.ReasonCode)
+	n.Description = strings.TrimSpace(n.Description)
+
+	reasons, ok := noteReasons[n.Type]
+	if !ok {
+		details = append(details, FieldError{Field: "tipo", Message: "Debe ser 'credito' o 'debito'."})
+	} else if description, ok := reasons[n.ReasonCode]; ok {
+		n.ReasonDescription = description
+	} else {
+		details = append(details, FieldError{Field: "codigo_concepto", Message: fmt.Sprintf("Para una nota %s debe ser uno de: %s.", n.Type, strings.Join(NoteReasonCodes(n.Type), ", "))})
+	}
+
+	if n.Date == "" {
+		n.Date = today.Format(NumberingDateLayout)
+	} else if _, err := time.Parse(NumberingDateLayout, n.Date); err != nil {
+		details = append(details, FieldError{Field: "fecha", Message: "Debe tener el formato AAAA-MM-DD."})
+	}
+
+	if len(details) > 0 {
+		return ValidationErrorInit(common.ErrInvalidNote, "La nota tiene datos inválidos.", details)
+	}
+	return nil
+}
+
+// FormatNumber antepone al consecutivo el prefijo del tipo de nota, p. ej. "NC12".
+func (n Note) FormatNumber(consecutive int64) string {
+	return fmt.Sprintf("%s%d", notePrefixes[n.Type], consecutive)
+}
+
+// Title es el nombre del documento que se imprime en el PDF.
+func (n Note) Title() string {
+	if n.Type == NoteTypeDebit {
+		return "Nota débito"
+	}
+	return "Nota crédito"
+}
+
+// NoteReasonDescription devuelve la descripción de un código de concepto, o
+// un texto vacío si el código no existe para ese tipo de nota.
+func NoteReasonDescription(noteType, code string) string {
+	return noteReasons[noteType][code]
+}
+
+// NoteReasonCodes devuelve, ordenados, los códigos de concepto válidos para un tipo de nota.
+func NoteReasonCodes(noteType string) []string {
+	codes := make([]string, 0, len(noteReasons[noteType]))
+	for code := range noteReasons[noteType] {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// CheckNoteAllowed devuelve un error NOTE_NOT_ALLOWED si no se pueden emitir
+// notas sobre una factura en el estado dado: solo las facturas emitidas,
+// enviadas o pagadas se corrigen con notas; los borradores se modifican
+// directamente y las facturas anuladas ya no tienen saldo.
+func CheckNoteAllowed(invoiceStatus string) error {
+	switch invoiceStatus {
+	case InvoiceStatusIssued, InvoiceStatusSent, InvoiceStatusPaid:
+		return nil
+	}
+	return ErrorResponseInit(common.ErrNoteNotAllowed, fmt.Sprintf("No se pueden emitir notas sobre una factura en estado '%s'.", invoiceStatus))
+}
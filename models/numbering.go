@@ -0,0 +1,112 @@
+package models
+
+import (
+	"facturaexpress/common"
+	"fmt"
+	"time"
+)
+
+// NumberingDateLayout es el formato de las fechas de la resolución.
+const NumberingDateLayout = "2006-01-02"
+
+// Umbrales a partir de los cuales la numeración genera advertencias.
+const (
+	NumberingWarningDays      = 30
+	NumberingWarningRemaining = 50
+)
+
+// Numbering es la numeración autorizada por una resolución de la DIAN para un
+// emisor. Current es el último consecutivo asignado; al configurar una
+// resolución nueva empieza en RangeFrom - 1.
+type Numbering struct {
+	ID               int64  `json:"id"`
+	UserID           int64  `json:"usuario_id"`
+	Prefix           string `json:"prefijo"`
+	ResolutionNumber string `json:"numero_resolucion"`
+	ResolutionDate   string `json:"fecha_resolucion"`
+	ValidFrom        string `json:"vigencia_desde"`
+	ValidTo          string `json:"vigencia_hasta"`
+	RangeFrom        int64  `json:"rango_desde"`
+	RangeTo          int64  `json:"rango_hasta"`
+	Current          int64  `json:"consecutivo_actual"`
+}
+
+// Next devuelve el siguiente consecutivo si la resolución está vigente en la
+// fecha dada y el rango no se ha agotado. No modifica Current.
+func (n Numbering) Next(today time.Time) (int64, error) {
+	day := today.Format(NumberingDateLayout)
+	if day < n.ValidFrom || day > n.ValidTo {
+		return 0, ErrorResponseInit(common.ErrNumberingExpired, fmt.Sprintf("La resolución %s no está vigente (del %s al %s).", n.ResolutionNumber, n.ValidFrom, n.ValidTo))
+	}
+	next := n.Current + 1
+	if next < n.RangeFrom {
+		next = n.RangeFrom
+	}
+	if next > n.RangeTo {
+		return 0, ErrorResponseInit(common.ErrNumberingExhausted, fmt.Sprintf("Se agotó el rango autorizado por la resolución %s (%s a %s).", n.ResolutionNumber, n.FormatNumber(n.RangeFrom), n.FormatNumber(n.RangeTo)))
+	}
+	return next, nil
+}
+
+// FormatNumber antepone el prefijo al consecutivo, p. ej. "FE1024".
+func (n Numbering) FormatNumber(consecutive int64) string {
+	return fmt.Sprintf("%s%d", n.Prefix, consecutive)
+}
+
+// Remaining es la cantidad de números que quedan por asignar.
+func (n Numbering) Remaining() int64 {
+	last := n.Current
+	if last < n.RangeFrom-1 {
+		last = n.RangeFrom - 1
+	}
+	return n.RangeTo - last
+}
+
+// Warnings avisa cuando la resolución vence en menos de NumberingWarningDays
+// días o quedan NumberingWarningRemaining números o menos, para que el emisor
+// tramite una nueva a tiempo.
+func (n Numbering) Warnings(today time.Time) []string {
+	warnings := []string{}
+	if validTo, err := time.Parse(NumberingDateLayout, n.ValidTo); err == nil {
+		day, _ := time.Parse(NumberingDateLayout, today.Format(NumberingDateLayout))
+		days := int(validTo.Sub(day).Hours() / 24)
+		switch {
+		case days < 0:
+			warnings = append(warnings, fmt.Sprintf("La resolución %s venció el %s.", n.ResolutionNumber, n.ValidTo))
+		case days <= NumberingWarningDays:
+			warnings = append(warnings, fmt.Sprintf("La resolución %s vence en %d días (%s).", n.ResolutionNumber, days, n.ValidTo))
+		}
+	}
+	switch remaining := n.Remaining(); {
+	case remaining <= 0:
+		warnings = append(warnings, fmt.Sprintf("Se agotó el rango de la resolución %s.", n.ResolutionNumber))
+	case remaining <= NumberingWarningRemaining:
+		warnings = append(warnings, fmt.Sprintf("Quedan %d números del rango de la resolución %s.", remaining, n.ResolutionNumber))
+	}
+	return warnings
+}
+
+// Validate revisa los datos de la resolución antes de guardarla.
+func (n Numbering) Validate() error {
+	if n.ResolutionNumber == "" || n.ResolutionDate == "" || n.ValidFrom == "" || n.ValidTo == "" {
+		return ErrorResponseInit(common.ErrMissingFields, "Faltan datos de la resolución: numero_resolucion, fecha_resolucion, vigencia_desde y vigencia_hasta son obligatorios.")
+	}
+	for _, date := range []string{n.ResolutionDate, n.ValidFrom, n.ValidTo} {
+		if _, err := time.Parse(NumberingDateLayout, date); err != nil {
+			return ErrorResponseInit(common.ErrInvalidNumbering, fmt.Sprintf("La fecha '%s' no es válida; usa el formato AAAA-MM-DD.", date))
+		}
+	}
+	if n.ValidTo < n.ValidFrom {
+		return ErrorResponseInit(common.ErrInvalidNumbering, "vigencia_hasta debe ser posterior a vigencia_desde.")
+	}
+	if len(n.Prefix) > 4 {
+		return ErrorResponseInit(common.ErrInvalidNumbering, "El prefijo puede tener máximo 4 caracteres.")
+	}
+	if n.RangeFrom <= 0 || n.RangeTo < n.RangeFrom {
+		return ErrorResponseInit(common.ErrInvalidNumbering, "El rango autorizado no es válido: rango_desde debe ser mayor que cero y no mayor que rango_hasta.")
+	}
+	if n.Current < n.RangeFrom-1 || n.Current > n.RangeTo {
+		return ErrorResponseInit(common.ErrInvalidNumbering, "El consecutivo actual está fuera del rango autorizado.")
+	}
+	return nil
+}
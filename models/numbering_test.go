@@ -0,0 +1,140 @@
+package models
+
+import (
+	"facturaexpress/common"
+	"testing"
+	"time"
+)
+
+func testNumbering(current int64) Numbering {
+	return Numbering{
+		Prefix:           "FE",
+		ResolutionNumber: "18760000001",
+		ResolutionDate:   "2024-01-01",
+		ValidFrom:        "2024-01-01",
+		ValidTo:          "2024-12-31",
+		RangeFrom:        1000,
+		RangeTo:          1002,
+		Current:          current,
+	}
+}
+
+func numberingDate(value string) time.Time {
+	day, err := time.Parse(NumberingDateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return day
+}
+
+func TestNumberingNext(t *testing.T) {
+	tests := []struct {
+		current int64
+		want    int64
+	}{
+		// Una resolución nueva empieza en rango_desde
+		{999, 1000},
+		{0, 1000},
+		{1000, 1001},
+		{1001, 1002},
+	}
+	for _, tt := range tests {
+		got, err := testNumbering(tt.current).Next(numberingDate("2024-06-15"))
+		if err != nil {
+			t.Errorf("Next con consecutivo %d: %v", tt.current, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Next con consecutivo %d = %d, se esperaba %d", tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestNumberingNextValidity(t *testing.T) {
+	numbering := testNumbering(999)
+	for _, day := range []string{"2024-01-01", "2024-12-31"} {
+		if _, err := numbering.Next(numberingDate(day)); err != nil {
+			t.Errorf("Next el %s: %v", day, err)
+		}
+	}
+	for _, day := range []string{"2023-12-31", "2025-01-01"} {
+		_, err := numbering.Next(numberingDate(day))
+		if errJSON, ok := err.(*ErrorJson); !ok || errJSON.Title != common.ErrNumberingExpired {
+			t.Errorf("Next el %s: se esperaba %s, se obtuvo %v", day, common.ErrNumberingExpired, err)
+		}
+	}
+}
+
+func TestNumberingNextUsesColombianDay(t *testing.T) {
+	// 2025-01-01 a las 03:00 UTC todavía es 31 de diciembre en Colombia
+	newYearUTC := time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC)
+	numbering := testNumbering(999)
+	if _, err := numbering.Next(newYearUTC.In(Bogota)); err != nil {
+		t.Errorf("Next el 31 de diciembre en Colombia: %v", err)
+	}
+	if got := numbering.Warnings(newYearUTC.In(Bogota)); len(got) != 2 || got[0] != "La resolución 18760000001 vence en 0 días (2024-12-31)." {
+		t.Errorf("Warnings el 31 de diciembre en Colombia = %v", got)
+	}
+}
+
+func TestNumberingNextExhausted(t *testing.T) {
+	_, err := testNumbering(1002).Next(numberingDate("2024-06-15"))
+	if errJSON, ok := err.(*ErrorJson); !ok || errJSON.Title != common.ErrNumberingExhausted {
+		t.Fatalf("se esperaba %s, se obtuvo %v", common.ErrNumberingExhausted, err)
+	}
+}
+
+func TestNumberingFormatAndRemaining(t *testing.T) {
+	numbering := testNumbering(999)
+	if got := numbering.FormatNumber(1000); got != "FE1000" {
+		t.Errorf("FormatNumber(1000) = %q", got)
+	}
+	if got := numbering.Remaining(); got != 3 {
+		t.Errorf("Remaining con consecutivo 999 = %d, se esperaban 3", got)
+	}
+	if got := testNumbering(1002).Remaining(); got != 0 {
+		t.Errorf("Remaining con el rango agotado = %d", got)
+	}
+}
+
+func TestNumberingWarnings(t *testing.T) {
+	// Quedan 3 números, menos que el umbral
+	if got := testNumbering(999).Warnings(numberingDate("2024-01-01")); len(got) != 1 {
+		t.Errorf("Warnings = %v, se esperaba solo la del rango", got)
+	}
+
+	numbering := testNumbering(999)
+	numbering.RangeTo = 100000
+	if got := numbering.Warnings(numberingDate("2024-06-15")); len(got) != 0 {
+		t.Errorf("Warnings = %v, no se esperaban advertencias", got)
+	}
+	if got := numbering.Warnings(numberingDate("2024-12-10")); len(got) != 1 {
+		t.Errorf("Warnings a 21 días del vencimiento = %v", got)
+	}
+	if got := numbering.Warnings(numberingDate("2025-01-10")); len(got) != 1 {
+		t.Errorf("Warnings con la resolución vencida = %v", got)
+	}
+}
+
+func TestNumberingValidate(t *testing.T) {
+	if err := testNumbering(999).Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	invalid := map[string]func(n *Numbering){
+		"sin resolución":              func(n *Numbering) { n.ResolutionNumber = "" },
+		"fecha inválida":              func(n *Numbering) { n.ValidTo = "31/12/2024" },
+		"vigencia invertida":          func(n *Numbering) { n.ValidTo = "2023-12-31" },
+		"prefijo largo":               func(n *Numbering) { n.Prefix = "FACTU" },
+		"rango invertido":             func(n *Numbering) { n.RangeTo = 999 },
+		"consecutivo fuera":           func(n *Numbering) { n.Current = 1003 },
+		"consecutivo antes del rango": func(n *Numbering) { n.Current = 998 },
+	}
+	for name, change := range invalid {
+		numbering := testNumbering(999)
+		change(&numbering)
+		if err := numbering.Validate(); err == nil {
+			t.Errorf("%s: Validate no devolvió error", name)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"facturaexpress/common"
+	"strings"
+	"time"
+)
+
+// Medios de pago admitidos.
+const (
+	PaymentMethodCash     = "efectivo"
+	PaymentMethodTransfer = "transferencia"
+	PaymentMethodDeposit  = "consignacion"
+	PaymentMethodCheck    = "cheque"
+	PaymentMethodCard     = "tarjeta"
+	PaymentMethodOther    = "otro"
+)
+
+// PaymentMethods enumera los medios de pago válidos.
+var PaymentMethods = []string{PaymentMethodCash, PaymentMethodTransfer, PaymentMethodDeposit, PaymentMethodCheck, PaymentMethodCard, PaymentMethodOther}
+
+// Estado de pago de una factura según su saldo.
+const (
+	PaymentStatusPending = "pendiente"
+	PaymentStatusPartial = "parcial"
+	PaymentStatusPaid    = "pagado"
+)
+
+// Payment es un movimiento del libro de pagos de una factura. Los pagos no se
+// modifican ni se eliminan: un pago se reversa con otro movimiento por el
+// valor negativo, cuyo ReversalOf apunta al pago original.
+type Payment struct {
+	ID         int64     `json:"id"`
+	InvoiceID  int64     `json:"factura_id"`
+	Amount     Money     `json:"valor"`
+	Date       string    `json:"fecha"`
+	Method     string    `json:"metodo"`
+	Reference  string    `json:"referencia"`
+	Reason     string    `json:"motivo,omitempty"`
+	ReversalOf *int64    `json:"reversa_de,omitempty"`
+	Reversed   bool      `json:"reversado"`
+	UserID     int64     `json:"usuario_id"`
+	CreatedAt  time.Time `json:"creado_en"`
+}
+
+// Validate normaliza el medio de pago y la referencia, completa la fecha (hoy
+// si no viene) y devuelve un error INVALID_PAYMENT con el detalle de cada
+// campo inválido.
+func (p *Payment) Validate(today time.Time) error {
+	var details []FieldError
+	p.Method = strings.ToLower(strings.TrimSpace(p.Method))
+	p.Reference = strings.TrimSpace(p.Reference)
+
+	if p.Amount <= 0 {
+		details = append(details, FieldError{Field: "valor", Message: "Debe ser mayor que cero."})
+	}
+	if !isPaymentMethod(p.Method) {
+		details = append(details, FieldError{Field: "metodo", Message: "Debe ser uno de: " + strings.Join(PaymentMethods, ", ") + "."})
+	}
+	if p.Date == "" {
+		p.Date = today.Format(NumberingDateLayout)
+	} else if date, err := time.Parse(NumberingDateLayout, p.Date); err != nil {
+		details = append(details, FieldError{Field: "fecha", Message: "Debe tener el formato AAAA-MM-DD."})
+	} else if date.After(today) {
+		details = append(details, FieldError{Field: "fecha", Message: "No puede ser una fecha futura."})
+	}
+
+	if len(details) > 0 {
+		return ValidationErrorInit(common.ErrInvalidPayment, "El pago tiene datos inválidos.", details)
+	}
+	return nil
+}
+
+func isPaymentMethod(method string) bool {
+	for _, valid := range PaymentMethods {
+		if method == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckPaymentAllowed devuelve un error PAYMENT_NOT_ALLOWED si no se pueden
+// registrar pagos sobre una factura en el estado dado: los borradores aún no
+// se cobran y las facturas anuladas ya no.
+func CheckPaymentAllowed(invoiceStatus string) error {
+	switch invoiceStatus {
+	case InvoiceStatusIssued, InvoiceStatusSent, InvoiceStatusPaid:
+		return nil
+	}
+	return ErrorResponseInit(common.ErrPaymentNotAllowed, "No se pueden registrar pagos sobre una factura en estado '"+invoiceStatus+"'.")
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PDFTemplate es una plantilla de PDF guardada. Layout es el diseño
+// declarativo que interpreta el paquete pdftemplate. Default marca la
+// plantilla que usan los usuarios que no tienen una asignada.
+type PDFTemplate struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"nombre"`
+	Description string          `json:"descripcion"`
+	Default     bool            `json:"predeterminada"`
+	Layout      json.RawMessage `json:"diseno"`
+	UpdatedAt   time.Time       `json:"actualizado_en"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// OperatorProfile son los datos de operador que un usuario guarda una vez para
+// no escribirlos en cada factura. Puede tener varias cuentas bancarias; la
+// marcada como predeterminada es la que se usa al crear facturas. Images
+// describe el logo y la firma subidos, sin su contenido; se administran aparte
+// y guardar el perfil no los cambia.
+type OperatorProfile struct {
+	UserID               int64          `json:"usuario_id"`
+	Name                 string         `json:"nombre"`
+	DocumentType         string         `json:"tipo_documento"`
+	Document             string         `json:"documento"`
+	DocumentIssuanceCity string         `json:"ciudad_expedicion_documento"`
+	Cellphone            string         `json:"celular"`
+	BankAccounts         []BankAccount  `json:"cuentas_bancarias"`
+	Images               []ProfileImage `json:"imagenes"`
+}
+
+// Tipos de imagen del perfil.
+const (
+	ProfileImageLogo      = "logo"
+	ProfileImageSignature = "firma"
+)
+
+// ProfileImageKinds son los tipos de imagen que se pueden subir al perfil.
+var ProfileImageKinds = []string{ProfileImageLogo, ProfileImageSignature}
+
+// ProfileImage es el logo o la firma del perfil: una imagen PNG o JPEG.
+// Content solo se llena cuando se necesita la imagen misma.
+type ProfileImage struct {
+	UserID      int64     `json:"-"`
+	Kind        string    `json:"tipo"`
+	ContentType string    `json:"tipo_contenido"`
+	Size        int       `json:"tamano"`
+	Content     []byte    `json:"-"`
+	UpdatedAt   time.Time `json:"actualizado_en"`
+}
+
+// BankAccount es una cuenta bancaria del perfil de operador.
+type BankAccount struct {
+	ID      int64  `json:"id"`
+	Number  string `json:"numero"`
+	Type    string `json:"tipo"`
+	Bank    string `json:"banco"`
+	Default bool   `json:"predeterminada"`
+}
+
+// DefaultBankAccount devuelve la cuenta predeterminada del perfil.
+func (p OperatorProfile) DefaultBankAccount() (BankAccount, bool) {
+	for _, account := range p.BankAccounts {
+		if account.Default {
+			return account, true
+		}
+	}
+	return BankAccount{}, false
+}
+
+// FillOperator completa los campos vacíos del operador de una factura con los
+// del perfil. Los datos bancarios se toman juntos: de la cuenta del perfil con
+// el número indicado o, si no se indicó número, de la cuenta predeterminada.
+func (p OperatorProfile) FillOperator(operator *Operator) {
+	fill := func(field *string, value string) {
+		if *field == "" {
+			*field = value
+		}
+	}
+	fill(&operator.Name, p.Name)
+	if operator.DocumentType == "" && operator.Document == "" {
+		operator.DocumentType = p.DocumentType
+		operator.Document = p.Document
+	}
+	fill(&operator.DocumentIssuanceCity, p.DocumentIssuanceCity)
+	fill(&operator.Cellphone, p.Cellphone)
+
+	account, ok := p.DefaultBankAccount()
+	if operator.BankAccountNumber != "" {
+		ok = false
+		for _, candidate := range p.BankAccounts {
+			if candidate.Number == operator.BankAccountNumber {
+				account, ok = candidate, true
+			}
+		}
+	}
+	if ok {
+		fill(&operator.BankAccountNumber, account.Number)
+		fill(&operator.BankAccountType, account.Type)
+		fill(&operator.Bank, account.Bank)
+	}
+}
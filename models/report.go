@@ -0,0 +1,47 @@
+package models
+
+// Fechas desde las que se cuenta la antigüedad de la cartera.
+const (
+	AgingBasisDate    = "fecha"
+	AgingBasisDueDate = "vencimiento"
+)
+
+// AgingFilter describe qué facturas entran en el informe de cartera. UserID
+// en 0 significa facturas de todos los usuarios; los campos vacíos no filtran.
+// AsOf es la fecha de corte (AAAA-MM-DD) y Basis la fecha desde la que se
+// cuentan los días: la de la factura o la de vencimiento, que cae en la de la
+// factura cuando no la tiene.
+type AgingFilter struct {
+	UserID           int64
+	OperatorDocument string
+	AsOf             string
+	Basis            string
+}
+
+// AgingBuckets reparte los saldos pendientes por días transcurridos. Las
+// facturas que aún no vencen cuentan en Days0To30.
+type AgingBuckets struct {
+	Days0To30  Money `json:"0_30"`
+	Days31To60 Money `json:"31_60"`
+	Days61To90 Money `json:"61_90"`
+	Over90     Money `json:"mas_de_90"`
+	Total      Money `json:"total"`
+}
+
+// Add suma otros saldos a los de cada rango.
+func (b *AgingBuckets) Add(other AgingBuckets) {
+	b.Days0To30 += other.Days0To30
+	b.Days31To60 += other.Days31To60
+	b.Days61To90 += other.Days61To90
+	b.Over90 += other.Over90
+	b.Total += other.Total
+}
+
+// AgingRow es la cartera pendiente de una empresa, identificada por su NIT sin
+// dígito de verificación.
+type AgingRow struct {
+	CompanyTIN  string `json:"nit_empresa"`
+	CompanyName string `json:"nombre_empresa"`
+	Invoices    int    `json:"facturas"`
+	AgingBuckets
+}
@@ -0,0 +1,84 @@
+package pdftemplate
+
+import (
+	"container/list"
+	"encoding/json"
+	"facturaexpress/helpers"
+	"sync"
+)
+
+// Cache guarda PDF ya generados hasta sumar maxBytes; al pasarse descarta los
+// menos usados recientemente. Es seguro usarlo desde varias goroutines. Una
+// caché de tamaño 0, o nil, no guarda nada.
+type Cache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	size     int64
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key string
+	pdf []byte
+}
+
+func NewCache(maxBytes int64) *Cache {
+	return &Cache{maxBytes: maxBytes, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// Fingerprint identifica el PDF que resulta de aplicar el diseño a los datos:
+// cambia si cambia cualquier dato de la factura o de la plantilla. Tiene el
+// formato de un ETag y sirve de clave en la caché.
+func Fingerprint(tpl Template, data Data) (string, error) {
+	content, err := json.Marshal(struct {
+		Template Template
+		Data     Data
+	}{tpl, data})
+	if err != nil {
+		return "", err
+	}
+	return helpers.ComputeETag(content), nil
+}
+
+// Get devuelve el PDF guardado con esa clave y lo marca como usado.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheEntry).pdf, true
+}
+
+// Add guarda un PDF. Los que por sí solos superan el tamaño de la caché no se guardan.
+func (c *Cache) Add(key string, pdf []byte) {
+	if c == nil || int64(len(pdf)) > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, pdf: pdf})
+	c.size += int64(len(pdf))
+	for c.size > c.maxBytes {
+		oldest := c.order.Back()
+		entry := oldest.Value.(*cacheEntry)
+		c.order.Remove(oldest)
+		delete(c.entries, entry.key)
+		c.size -= int64(len(entry.pdf))
+	}
+}
+
+// Enabled indica si la caché guarda algo.
+func (c *Cache) Enabled() bool {
+	return c != nil && c.maxBytes > 0
+}
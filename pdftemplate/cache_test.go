@@ -0,0 +1,116 @@
+package pdftemplate
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func pdfOfSize(size int) []byte {
+	return bytes.Repeat([]byte{'x'}, size)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(30)
+	cache.Add("a", pdfOfSize(10))
+	cache.Add("b", pdfOfSize(10))
+	cache.Add("c", pdfOfSize(10))
+
+	// Usar "a" la deja como la más reciente, así que al pasarse sale "b"
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("falta a")
+	}
+	cache.Add("d", pdfOfSize(10))
+	if _, ok := cache.Get("b"); ok {
+		t.Error("b debió descartarse")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("falta %s", key)
+		}
+	}
+
+	// Un PDF grande descarta los que hagan falta
+	cache.Add("e", pdfOfSize(25))
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("%s debió descartarse", key)
+		}
+	}
+	if pdf, ok := cache.Get("e"); !ok || len(pdf) != 25 {
+		t.Error("falta e")
+	}
+	if cache.size != 25 {
+		t.Errorf("tamaño %d, se esperaba 25", cache.size)
+	}
+}
+
+func TestCacheSkipsOversizedAndRepeatedEntries(t *testing.T) {
+	cache := NewCache(10)
+	cache.Add("a", pdfOfSize(11))
+	if _, ok := cache.Get("a"); ok {
+		t.Error("se guardó un PDF más grande que la caché")
+	}
+
+	cache.Add("b", pdfOfSize(6))
+	cache.Add("b", pdfOfSize(6))
+	if cache.size != 6 || cache.order.Len() != 1 {
+		t.Errorf("la clave repetida se contó dos veces: tamaño %d, %d entradas", cache.size, cache.order.Len())
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	var missing *Cache
+	missing.Add("a", pdfOfSize(1))
+	if _, ok := missing.Get("a"); ok || missing.Enabled() {
+		t.Error("una caché nil no debe guardar nada")
+	}
+
+	empty := NewCache(0)
+	empty.Add("a", pdfOfSize(1))
+	if _, ok := empty.Get("a"); ok || empty.Enabled() {
+		t.Error("una caché de tamaño 0 no debe guardar nada")
+	}
+}
+
+func TestCacheConcurrentUse(t *testing.T) {
+	cache := NewCache(100)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("%d-%d", i, j%20)
+				cache.Add(key, pdfOfSize(j%7+1))
+				cache.Get(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if cache.size > 100 {
+		t.Errorf("la caché superó su tamaño: %d", cache.size)
+	}
+}
+
+func TestFingerprintChangesWithData(t *testing.T) {
+	tpl := Default()
+	data := sampleData()
+	first, err := Fingerprint(tpl, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := Fingerprint(tpl, data)
+	if first != again {
+		t.Error("la huella no es estable")
+	}
+
+	data.Invoice.TotalPaid++
+	changedData, _ := Fingerprint(tpl, data)
+	tpl.Labels = map[string]string{"ciudad": "Bogotá"}
+	changedTemplate, _ := Fingerprint(tpl, data)
+	if changedData == first || changedTemplate == changedData {
+		t.Error("la huella no cambió con la factura o la plantilla")
+	}
+}
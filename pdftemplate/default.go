@@ -0,0 +1,66 @@
+package pdftemplate
+
+import (
+	_ "embed"
+	"encoding/json"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+)
+
+// defaultLayout es el diseño que se usa cuando el usuario no tiene una
+// plantilla asignada y ninguna está marcada como predeterminada.
+//
+//go:embed default.json
+var defaultLayout []byte
+
+var defaultTemplate = mustDecode(defaultLayout)
+
+func mustDecode(layout []byte) Template {
+	var tpl Template
+	if err := json.Unmarshal(layout, &tpl); err != nil {
+		panic("pdftemplate: el diseño predeterminado no es válido: " + err.Error())
+	}
+	return tpl
+}
+
+// Default devuelve el diseño predeterminado.
+func Default() Template {
+	return mustDecode(defaultLayout)
+}
+
+// DefaultLayout devuelve el JSON del diseño predeterminado, como punto de
+// partida para escribir otros.
+func DefaultLayout() []byte {
+	return append([]byte(nil), defaultLayout...)
+}
+
+// sampleData es una factura con todos los campos llenos con la que se prueba
+// un diseño antes de guardarlo.
+func sampleData() Data {
+	invoice := models.Invoice{
+		Number:       "FE-1",
+		Date:         "2025-01-15T10:00:00-05:00",
+		Status:       models.InvoiceStatusDraft,
+		Company:      models.Company{Name: "Empresa de Ejemplo S.A.S.", TIN: "900123456-8", Address: "Calle 1 # 2-3", City: "Bogotá"},
+		Operator:     models.Operator{Name: "Operador de Ejemplo", DocumentType: "CC", Document: "1020304050", DocumentIssuanceCity: "Cartagena", Cellphone: "3001234567", BankAccountNumber: "123456789", BankAccountType: "Ahorros", Bank: "Banco"},
+		Services:     []models.Service{{Description: "Servicio de ejemplo", Quantity: 2, Unit: "horas", UnitPrice: 5000000, Discount: 100000, Subtotal: 9900000, IVARate: "19", IVAAmount: 1881000}},
+		Taxes:        []models.TaxLine{{Type: "iva", Description: "IVA 19%", Base: 9900000, Rate: 19, Amount: 1881000}, {Type: "retefuente", Description: "Retención en la fuente", Base: 9900000, Rate: 4, Amount: 396000}},
+		Subtotal:     9900000,
+		TotalIVA:     1881000,
+		TotalValue:   11781000,
+		NetPayable:   11385000,
+		TotalCredits: 100000,
+		TotalDebits:  100000,
+		TotalPaid:    100000,
+		CUFE:         "ejemplo",
+	}
+	invoice.AmountInWords = helpers.AmountInWords(invoice.NetPayable)
+	invoice.ComputeBalance()
+	data := Data{Invoice: invoice, QRContent: "https://catalogo-vpfe.dian.gov.co", VerificationURL: "https://catalogo-vpfe.dian.gov.co"}
+	// Cualquier PNG sirve para probar los bloques de imagen
+	if sample, err := helpers.QRCodePNG("ejemplo", 60); err == nil {
+		data.Logo = &Image{Type: "PNG", Content: sample}
+		data.Signature = &Image{Type: "PNG", Content: sample}
+	}
+	return data
+}
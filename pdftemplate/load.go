@@ -0,0 +1,26 @@
+package pdftemplate
+
+import (
+	"database/sql"
+	"facturaexpress/interfaces"
+)
+
+// ForUser devuelve el diseño con que se generan los PDF de un usuario: el de
+// la plantilla que tiene asignada, el de la predeterminada o, si no hay
+// plantillas, el incluido en la aplicación. Las fuentes se buscan en fontDir.
+// El diseño guardado solo se decodifica: se validó al crear o modificar la
+// plantilla.
+func ForUser(templates interfaces.PDFTemplateRepository, userID int64, fontDir string) (Template, error) {
+	stored, err := templates.ForUser(userID)
+	if err == sql.ErrNoRows {
+		tpl := Default()
+		tpl.FontDir = fontDir
+		return tpl, nil
+	}
+	if err != nil {
+		return Template{}, err
+	}
+	tpl, err := Decode(stored.Layout, fontDir)
+	tpl.UpdatedAt = stored.UpdatedAt
+	return tpl, err
+}
@@ -0,0 +1,414 @@
+package pdftemplate
+
+import (
+	"bytes"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"facturaexpress/taxes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Data son los datos a los que se enlaza un diseño. QRContent es el
+// contenido del código QR, que no se dibuja si está vacío. Labels son las
+// etiquetas del diseño; Render las llena. Logo y Signature son las imágenes
+// del perfil del dueño de la factura, o nil si no las ha subido.
+type Data struct {
+	Invoice         models.Invoice
+	Labels          map[string]string
+	QRContent       string
+	VerificationURL string
+	Logo            *Image
+	Signature       *Image
+}
+
+// Image es una imagen que el diseño dibuja con un bloque imagen. Type es el
+// tipo de imagen de gofpdf: PNG o JPG.
+type Image struct {
+	Type    string
+	Content []byte
+}
+
+// SetImages toma el logo y la firma de las imágenes del perfil.
+func (d *Data) SetImages(images []models.ProfileImage) {
+	for _, profileImage := range images {
+		image := &Image{Type: helpers.ProfileImagePDFType(profileImage.ContentType), Content: profileImage.Content}
+		switch profileImage.Kind {
+		case models.ProfileImageLogo:
+			d.Logo = image
+		case models.ProfileImageSignature:
+			d.Signature = image
+		}
+	}
+}
+
+func (d Data) image(kind string) *Image {
+	switch kind {
+	case models.ProfileImageLogo:
+		return d.Logo
+	case models.ProfileImageSignature:
+		return d.Signature
+	}
+	return nil
+}
+
+// textFuncs son las funciones disponibles en los textos de un diseño.
+var textFuncs = template.FuncMap{
+	"fecha":    helpers.FormatDateInSpanish,
+	"dinero":   func(value models.Money) string { return value.Format() },
+	"cantidad": func(value float64) string { return strconv.FormatFloat(value, 'f', -1, 64) },
+	// unir junta con sep los valores que no están vacíos
+	"unir": func(sep string, values ...string) string {
+		var parts []string
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				parts = append(parts, value)
+			}
+		}
+		return strings.Join(parts, sep)
+	},
+}
+
+// NewDocument crea un documento con el papel, los márgenes y las fuentes del
+// diseño, sin páginas.
+func NewDocument(tpl Template) *gofpdf.Fpdf {
+	pdf := gofpdf.New(tpl.Page.Orientation, "mm", tpl.Page.Size, "")
+	margins := tpl.Page.Margins
+	pdf.SetMargins(margins.Left, margins.Top, margins.Right)
+	pdf.SetAutoPageBreak(true, margins.Bottom)
+	for _, font := range tpl.Fonts {
+		path, err := font.Path(tpl.FontDir)
+		if err != nil {
+			pdf.SetError(err)
+			break
+		}
+		pdf.AddUTF8Font(font.Family, font.Style, path)
+	}
+	return pdf
+}
+
+// Render dibuja la factura de data con el diseño y escribe el PDF en w.
+func Render(tpl Template, data Data, w io.Writer) error {
+	data.Labels = tpl.Labels
+	r := &renderer{pdf: NewDocument(tpl), tpl: tpl, data: data, texts: map[string]*template.Template{}}
+	// Una fuente que no se pudo cargar hace fallar a SplitText con un pánico
+	if err := r.pdf.Error(); err != nil {
+		return err
+	}
+	if tpl.Footer != nil {
+		if err := r.setFooter(*tpl.Footer); err != nil {
+			return err
+		}
+	}
+	r.pdf.AddPage()
+
+	for i, section := range tpl.Sections {
+		field := fmt.Sprintf("secciones[%d]", i)
+		if ok, err := r.condition(field+".si", section.If); err != nil || !ok {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		r.font = mergeFont(tpl.Font, section.Font)
+		for j, block := range section.Blocks {
+			if err := r.block(fmt.Sprintf("%s.bloques[%d]", field, j), block); err != nil {
+				return err
+			}
+		}
+		if section.SpaceAfter > 0 {
+			r.pdf.Ln(section.SpaceAfter)
+		}
+	}
+
+	if err := r.pdf.Error(); err != nil {
+		return err
+	}
+	return r.pdf.Output(w)
+}
+
+// totalPagesAlias es el texto que gofpdf reemplaza por el número de páginas.
+const totalPagesAlias = "{nb}"
+
+type renderer struct {
+	pdf   *gofpdf.Fpdf
+	tpl   Template
+	data  Data
+	font  Font
+	texts map[string]*template.Template
+}
+
+func (r *renderer) block(field string, block Block) error {
+	if ok, err := r.condition(field+".si", block.If); err != nil || !ok {
+		return err
+	}
+	font := mergeFont(r.font, block.Font)
+	r.setFont(font)
+
+	switch block.Type {
+	case BlockText:
+		text, err := r.text(field+".texto", block.Text)
+		if err != nil {
+			return err
+		}
+		height := orDefault(block.Height, 10)
+		r.pdf.CellFormat(block.Width, height, text, "", 0, orDefaultAlign(block.Align), false, 0, "")
+		r.pdf.Ln(orDefault(block.Advance, height))
+	case BlockParagraph:
+		text, err := r.text(field+".texto", block.Text)
+		if err != nil {
+			return err
+		}
+		r.pdf.MultiCell(block.Width, orDefault(block.Height, 7), text, "", orDefaultAlign(block.Align), false)
+		if block.Advance > 0 {
+			r.pdf.Ln(block.Advance)
+		}
+	case BlockRow:
+		height := orDefault(block.Height, 10)
+		for i, column := range block.Columns {
+			columnField := fmt.Sprintf("%s.columnas[%d]", field, i)
+			if ok, err := r.condition(columnField+".si", column.If); err != nil || !ok {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			text, err := r.text(columnField+".texto", column.Text)
+			if err != nil {
+				return err
+			}
+			r.setFont(mergeFont(font, column.Font))
+			r.pdf.CellFormat(column.Width, height, text, "", 0, orDefaultAlign(column.Align), false, 0, "")
+		}
+		r.pdf.Ln(orDefault(block.Advance, height))
+	case BlockSpace:
+		r.pdf.Ln(block.Height)
+	case BlockServices:
+		r.services(block, font)
+	case BlockSummary:
+		r.summary(block, font)
+	case BlockQRCode:
+		return r.qrCode(field, block)
+	case BlockImage:
+		return r.image(block)
+	}
+	return nil
+}
+
+// setFooter hace que cada página termine con el pie: su texto a la izquierda
+// y el número de página a la derecha. El total de páginas se conoce al cerrar
+// el documento, así que se escribe con un alias que gofpdf reemplaza al final.
+func (r *renderer) setFooter(footer Footer) error {
+	text, err := r.text("pie.texto", footer.Text)
+	if err != nil {
+		return err
+	}
+	font := mergeFont(r.tpl.Font, footer.Font)
+	height := orDefault(footer.Height, 10)
+	margins := r.tpl.Page.Margins
+	r.pdf.AliasNbPages(totalPagesAlias)
+	r.pdf.SetFooterFunc(func() {
+		_, pageHeight := r.pdf.GetPageSize()
+		y := pageHeight - margins.Bottom + (margins.Bottom-height)/2
+		r.setFont(font)
+		r.pdf.SetXY(margins.Left, y)
+		r.pdf.CellFormat(0, height, text, "", 0, "L", false, 0, "")
+		if label := r.data.Labels["pagina"]; label != "" {
+			page := strings.NewReplacer("{pagina}", strconv.Itoa(r.pdf.PageNo()), "{paginas}", totalPagesAlias).Replace(label)
+			r.pdf.SetXY(margins.Left, y)
+			r.pdf.CellFormat(0, height, page, "", 0, "R", false, 0, "")
+		}
+	})
+	return nil
+}
+
+// summary dibuja el subtotal, el IVA por tarifa, el total, las retenciones y
+// el valor neto y, si la factura tiene notas o pagos, cómo llegan al saldo.
+func (r *renderer) summary(block Block, font Font) {
+	height := orDefault(block.Height, 8)
+	width := orDefault(block.Width, 120)
+	highlightFont := mergeFont(font, block.HighlightFont)
+	invoice := r.data.Invoice
+	labels := r.data.Labels
+	line := func(label string, value models.Money) {
+		r.pdf.CellFormat(width, height, label, "", 0, "L", false, 0, "")
+		r.pdf.CellFormat(50, height, "$ "+value.Format(), "", 1, "R", false, 0, "")
+	}
+
+	r.setFont(font)
+	line(labels["subtotal"], invoice.Subtotal)
+	for _, tax := range invoice.Taxes {
+		if tax.Type == taxes.TypeIVA && tax.Amount > 0 {
+			line(fmt.Sprintf("%s %s $ %s", tax.Description, labels["sobre"], tax.Base.Format()), tax.Amount)
+		}
+	}
+	line(labels["total"], invoice.TotalValue)
+	for _, tax := range invoice.Taxes {
+		if tax.Type != taxes.TypeIVA {
+			line(fmt.Sprintf("%s (%g%% %s $ %s)", tax.Description, tax.Rate, labels["sobre"], tax.Base.Format()), -tax.Amount)
+		}
+	}
+	r.setFont(highlightFont)
+	line(labels["valor_neto"], invoice.NetPayable)
+
+	// Las notas y los pagos cambian el saldo sin cambiar la factura
+	if invoice.TotalCredits != 0 || invoice.TotalDebits != 0 || invoice.TotalPaid != 0 {
+		r.setFont(font)
+		if invoice.TotalCredits != 0 {
+			line(labels["notas_credito"], -invoice.TotalCredits)
+		}
+		if invoice.TotalDebits != 0 {
+			line(labels["notas_debito"], invoice.TotalDebits)
+		}
+		if invoice.TotalPaid != 0 {
+			line(labels["pagos"], -invoice.TotalPaid)
+		}
+		r.setFont(highlightFont)
+		line(labels["saldo"], invoice.Balance)
+	}
+	r.setFont(font)
+}
+
+// qrCode dibuja el código QR con el texto a su derecha. Si no cabe en lo que
+// queda de la página pasa a una nueva.
+func (r *renderer) qrCode(field string, block Block) error {
+	if r.data.QRContent == "" {
+		return nil
+	}
+	qrCode, err := helpers.QRCodePNG(r.data.QRContent, 300)
+	if err != nil {
+		return err
+	}
+	text, err := r.text(field+".texto", block.Text)
+	if err != nil {
+		return err
+	}
+
+	size := orDefault(block.Height, 35)
+	left, _, _, _ := r.pdf.GetMargins()
+	if _, pageHeight := r.pdf.GetPageSize(); r.pdf.GetY()+size+10 > pageHeight-r.tpl.Page.Margins.Bottom {
+		r.pdf.AddPage()
+	}
+	y := r.pdf.GetY()
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+	r.pdf.RegisterImageOptionsReader("qr", options, bytes.NewReader(qrCode))
+	r.pdf.ImageOptions("qr", left, y, size, size, false, options, 0, "")
+	r.pdf.SetXY(left+size+5, y)
+	r.setFont(mergeFont(r.font, block.DetailFont))
+	r.pdf.MultiCell(0, 4, text, "", "L", false)
+	r.pdf.SetY(y + size)
+	if block.Advance > 0 {
+		r.pdf.Ln(block.Advance)
+	}
+	return nil
+}
+
+// image dibuja el logo o la firma con alto Height y, si Width es 0, el ancho
+// que conserva la proporción, alineada con Align. Si el usuario no ha subido
+// esa imagen no dibuja nada.
+func (r *renderer) image(block Block) error {
+	image := r.data.image(block.Image)
+	if image == nil {
+		return nil
+	}
+	options := gofpdf.ImageOptions{ImageType: image.Type}
+	info := r.pdf.RegisterImageOptionsReader(block.Image, options, bytes.NewReader(image.Content))
+	if info == nil || info.Height() == 0 {
+		return r.pdf.Error()
+	}
+
+	left, _, right, _ := r.pdf.GetMargins()
+	pageWidth, pageHeight := r.pdf.GetPageSize()
+	height := orDefault(block.Height, 20)
+	width := block.Width
+	if width == 0 {
+		width = height * info.Width() / info.Height()
+	}
+	// Una imagen más ancha que la página se reduce conservando la proporción
+	if available := pageWidth - left - right; width > available {
+		height = height * available / width
+		width = available
+	}
+	if r.pdf.GetY()+height > pageHeight-r.tpl.Page.Margins.Bottom {
+		r.pdf.AddPage()
+	}
+	x := left
+	switch block.Align {
+	case "C":
+		x = left + (pageWidth-left-right-width)/2
+	case "R":
+		x = pageWidth - right - width
+	}
+	y := r.pdf.GetY()
+	r.pdf.ImageOptions(block.Image, x, y, width, height, false, options, 0, "")
+	r.pdf.SetY(y)
+	r.pdf.Ln(orDefault(block.Advance, height))
+	return nil
+}
+
+// text evalúa un texto del diseño sobre los datos.
+func (r *renderer) text(field, text string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	compiled, ok := r.texts[text]
+	if !ok {
+		var err error
+		if compiled, err = newTextTemplate(field).Parse(text); err != nil {
+			return "", err
+		}
+		r.texts[text] = compiled
+	}
+	var buf bytes.Buffer
+	if err := compiled.Execute(&buf, r.data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// condition evalúa una condición del diseño; una condición vacía se cumple.
+func (r *renderer) condition(field, condition string) (bool, error) {
+	if condition == "" {
+		return true, nil
+	}
+	result, err := r.text(field, "{{if "+condition+"}}1{{end}}")
+	return result == "1", err
+}
+
+func (r *renderer) setFont(font Font) {
+	r.pdf.SetFont(font.Family, font.Style, font.Size)
+}
+
+// mergeFont devuelve base con los campos que override define.
+func mergeFont(base Font, override *Font) Font {
+	if override == nil {
+		return base
+	}
+	if override.Family != "" {
+		base.Family = override.Family
+		base.Style = override.Style
+	}
+	if override.Size != 0 {
+		base.Size = override.Size
+	}
+	return base
+}
+
+func orDefault(value, fallback float64) float64 {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+func orDefaultAlign(align string) string {
+	if align == "" {
+		return "L"
+	}
+	return align
+}
@@ -0,0 +1,170 @@
+package pdftemplate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Anchos de las columnas numéricas de la tabla de servicios; la descripción
+// ocupa el resto, pero nunca menos de minDescriptionWidth.
+const (
+	quantityColumnWidth  = 25
+	unitPriceColumnWidth = 35
+	lineTotalColumnWidth = 35
+	minDescriptionWidth  = 30
+)
+
+// tableLine es un renglón de una celda con la fuente con que se dibuja.
+type tableLine struct {
+	text string
+	font Font
+}
+
+type tableColumn struct {
+	width float64
+	align string
+}
+
+// services dibuja las líneas de la factura como una tabla con descripción,
+// cantidad, valor unitario y total. Cada celda se parte en renglones de alto
+// Height que caben en su columna, y una línea que no cabe en lo que queda de
+// la página sigue en la siguiente, donde se repite el encabezado.
+func (r *renderer) services(block Block, font Font) {
+	lineHeight := orDefault(block.Height, 6)
+	headerFont := mergeFont(font, block.HighlightFont)
+	detailFont := mergeFont(font, block.DetailFont)
+	labels := r.data.Labels
+
+	left, top, right, _ := r.pdf.GetMargins()
+	pageWidth, pageHeight := r.pdf.GetPageSize()
+	x := left
+	width := block.Width
+	if width == 0 || width > pageWidth-left-right {
+		width = pageWidth - left - right
+	}
+	descriptionWidth := width - quantityColumnWidth - unitPriceColumnWidth - lineTotalColumnWidth
+	if descriptionWidth < minDescriptionWidth {
+		descriptionWidth = minDescriptionWidth
+		width = descriptionWidth + quantityColumnWidth + unitPriceColumnWidth + lineTotalColumnWidth
+	}
+	columns := []tableColumn{
+		{width: descriptionWidth, align: "L"},
+		{width: quantityColumnWidth, align: "R"},
+		{width: unitPriceColumnWidth, align: "R"},
+		{width: lineTotalColumnWidth, align: "R"},
+	}
+	bottom := pageHeight - r.tpl.Page.Margins.Bottom
+	header := r.tableRow(columns, headerFont, labels["descripcion"], labels["cantidad"], labels["valor_unitario"], labels["valor_total"])
+	headerHeight := float64(rowSlots(header)) * lineHeight
+	// Renglones que caben en una página nueva, debajo del encabezado
+	pageSlots := int((bottom - top - headerHeight) / lineHeight)
+	if pageSlots < 1 {
+		pageSlots = 1
+	}
+
+	drawHeader := func() {
+		r.drawTableLines(x, columns, header, 0, rowSlots(header), lineHeight)
+		r.pdf.Line(x, r.pdf.GetY(), x+width, r.pdf.GetY())
+	}
+	// El encabezado no se deja solo al final de una página
+	if r.pdf.GetY()+headerHeight+lineHeight > bottom {
+		r.pdf.AddPage()
+	}
+	drawHeader()
+
+	for _, service := range r.data.Invoice.Services {
+		quantity := strconv.FormatFloat(service.Quantity, 'f', -1, 64)
+		if service.Unit != "" {
+			quantity += " " + service.Unit
+		}
+		row := r.tableRow(columns, font, service.Description, quantity, "$ "+service.UnitPrice.Format(), "$ "+service.Subtotal.Format())
+		var details []string
+		if service.Discount > 0 {
+			details = append(details, fmt.Sprintf("%s $ %s", labels["descuento"], service.Discount.Format()))
+		}
+		if service.IVAAmount > 0 {
+			details = append(details, fmt.Sprintf("%s %s%% $ %s", labels["iva"], service.IVARate, service.IVAAmount.Format()))
+		}
+		if len(details) > 0 {
+			row[0] = append(row[0], r.cellLines(columns[0].width, detailFont, strings.Join(details, " · "))...)
+		}
+
+		slots := rowSlots(row)
+		// Una línea que cabe entera en una página nueva no se parte
+		if available := int((bottom - r.pdf.GetY()) / lineHeight); slots > available && slots <= pageSlots {
+			r.pdf.AddPage()
+			drawHeader()
+		}
+		newPage := false
+		for drawn := 0; drawn < slots; {
+			available := int((bottom - r.pdf.GetY()) / lineHeight)
+			if available < 1 && !newPage {
+				r.pdf.AddPage()
+				drawHeader()
+				newPage = true
+				continue
+			}
+			// Si ni en una página nueva cabe un renglón, se dibuja uno de todos modos para avanzar
+			if available < 1 {
+				available = 1
+			}
+			count := slots - drawn
+			if count > available {
+				count = available
+			}
+			r.drawTableLines(x, columns, row, drawn, count, lineHeight)
+			drawn += count
+			newPage = false
+		}
+	}
+	r.pdf.Line(x, r.pdf.GetY(), x+width, r.pdf.GetY())
+	r.pdf.Ln(2)
+	r.setFont(font)
+}
+
+// tableRow parte el texto de cada celda en los renglones que caben en su columna.
+func (r *renderer) tableRow(columns []tableColumn, font Font, texts ...string) [][]tableLine {
+	row := make([][]tableLine, len(columns))
+	for i, column := range columns {
+		row[i] = r.cellLines(column.width, font, texts[i])
+	}
+	return row
+}
+
+func (r *renderer) cellLines(width float64, font Font, text string) []tableLine {
+	r.setFont(font)
+	var lines []tableLine
+	for _, line := range r.pdf.SplitText(strings.TrimSpace(text), width) {
+		lines = append(lines, tableLine{text: line, font: font})
+	}
+	return lines
+}
+
+// drawTableLines dibuja los renglones [from, from+count) de cada celda de la
+// fila y deja el cursor debajo de ellos.
+func (r *renderer) drawTableLines(x float64, columns []tableColumn, row [][]tableLine, from, count int, lineHeight float64) {
+	y := r.pdf.GetY()
+	cellX := x
+	for i, column := range columns {
+		for slot := from; slot < from+count && slot < len(row[i]); slot++ {
+			line := row[i][slot]
+			r.setFont(line.font)
+			r.pdf.SetXY(cellX, y+float64(slot-from)*lineHeight)
+			r.pdf.CellFormat(column.width, lineHeight, line.text, "", 0, column.align, false, 0, "")
+		}
+		cellX += column.width
+	}
+	r.pdf.SetXY(x, y+float64(count)*lineHeight)
+}
+
+// rowSlots es el número de renglones de la celda más alta de la fila, al menos uno.
+func rowSlots(row [][]tableLine) int {
+	slots := 1
+	for _, cell := range row {
+		if len(cell) > slots {
+			slots = len(cell)
+		}
+	}
+	return slots
+}
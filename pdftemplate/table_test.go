@@ -0,0 +1,199 @@
+package pdftemplate
+
+import (
+	"bytes"
+	"compress/zlib"
+	"facturaexpress/models"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// testTemplate es el diseño predeterminado, con las fuentes del repositorio.
+func testTemplate(t *testing.T) Template {
+	t.Helper()
+	tpl := Default()
+	tpl.FontDir = filepath.Join("..", "font")
+	return tpl
+}
+
+// setServicesHeight cambia el alto de los renglones de la tabla de servicios
+// y devuelve el campo del bloque.
+func setServicesHeight(t *testing.T, tpl *Template, height float64) string {
+	t.Helper()
+	for i := range tpl.Sections {
+		for j := range tpl.Sections[i].Blocks {
+			if tpl.Sections[i].Blocks[j].Type == BlockServices {
+				tpl.Sections[i].Blocks[j].Height = height
+				return fmt.Sprintf("diseno.secciones[%d].bloques[%d].alto", i, j)
+			}
+		}
+	}
+	t.Fatal("el diseño predeterminado no tiene tabla de servicios")
+	return ""
+}
+
+func TestValidateRejectsServicesRowTallerThanPage(t *testing.T) {
+	tpl := testTemplate(t)
+	field := setServicesHeight(t, &tpl, 300)
+
+	found := false
+	for _, detail := range tpl.validate() {
+		if detail.Field == field {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("validate no rechazó un alto de 300 mm en %s", field)
+	}
+
+	setServicesHeight(t, &tpl, 6)
+	if details := tpl.validate(); len(details) > 0 {
+		t.Fatalf("validate rechazó el diseño predeterminado: %v", details)
+	}
+}
+
+func TestRenderServicesRowTallerThanPageFinishes(t *testing.T) {
+	tpl := testTemplate(t)
+	setServicesHeight(t, &tpl, 300)
+
+	done := make(chan error, 1)
+	go func() { done <- Render(tpl, sampleData(), io.Discard) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Render falló: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Render no terminó con renglones más altos que la página")
+	}
+}
+
+// pdfPages devuelve el contenido de cada página del PDF, descomprimido.
+// gofpdf escribe cada página seguida de su contenido.
+func pdfPages(t *testing.T, pdf []byte) []string {
+	t.Helper()
+	var pages []string
+	for _, object := range bytes.Split(pdf, []byte("/Type /Page\n"))[1:] {
+		start := bytes.Index(object, []byte("stream\n"))
+		end := bytes.Index(object, []byte("\nendstream"))
+		if start < 0 || end < start {
+			t.Fatal("una página del PDF no tiene contenido")
+		}
+		reader, err := zlib.NewReader(bytes.NewReader(object[start+len("stream\n") : end]))
+		if err != nil {
+			t.Fatalf("contenido de la página %d: %v", len(pages)+1, err)
+		}
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("contenido de la página %d: %v", len(pages)+1, err)
+		}
+		pages = append(pages, string(content))
+	}
+	return pages
+}
+
+// pdfText es el texto como lo escribe gofpdf con una fuente UTF-8: en UTF-16BE.
+func pdfText(text string) string {
+	var encoded strings.Builder
+	for _, unit := range utf16.Encode([]rune(text)) {
+		encoded.WriteByte(byte(unit >> 8))
+		encoded.WriteByte(byte(unit))
+	}
+	return encoded.String()
+}
+
+func TestRenderServicesAcrossPages(t *testing.T) {
+	const lines = 300
+	tests := []struct {
+		name string
+		// long indica si la línea i tiene una descripción de varios renglones
+		long func(i int) bool
+		// fullPages indica si las páginas intermedias deben quedar llenas
+		fullPages bool
+	}{
+		{"un renglón por línea", func(int) bool { return false }, true},
+		{"líneas de varios renglones", func(i int) bool { return i%7 == 0 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tpl := testTemplate(t)
+			data := sampleData()
+			data.Invoice.Services = nil
+			for i := 1; i <= lines; i++ {
+				description := fmt.Sprintf("Servicio %03d", i)
+				if tt.long(i) {
+					description += strings.Repeat(" con una descripción que ocupa varios renglones", 4) + fmt.Sprintf(" fin %03d", i)
+				}
+				data.Invoice.Services = append(data.Invoice.Services, models.Service{Description: description, Quantity: 1, UnitPrice: 100000, Subtotal: 100000})
+			}
+
+			var out bytes.Buffer
+			if err := Render(tpl, data, &out); err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			pages := pdfPages(t, out.Bytes())
+			if len(pages) < 2 {
+				t.Fatalf("%d líneas cupieron en %d página(s)", lines, len(pages))
+			}
+
+			// Cada página lleva el pie con su número y el total de páginas
+			header := pdfText(tpl.Label("descripcion"))
+			for i, page := range pages {
+				footer := pdfText(fmt.Sprintf("Página %d de %d", i+1, len(pages)))
+				if !strings.Contains(page, footer) {
+					t.Errorf("la página %d no tiene el pie \"Página %d de %d\"", i+1, i+1, len(pages))
+				}
+				if i > 0 && i < len(pages)-1 && !strings.Contains(page, header) {
+					t.Errorf("la página %d no repite el encabezado de la tabla", i+1)
+				}
+			}
+
+			// Cada línea aparece una sola vez, entera en una página y en orden
+			perPage := make([]int, len(pages))
+			lastPage := 0
+			for i := 1; i <= lines; i++ {
+				marker := pdfText(fmt.Sprintf("Servicio %03d", i))
+				page, count := -1, 0
+				for j, content := range pages {
+					if n := strings.Count(content, marker); n > 0 {
+						page, count = j, count+n
+					}
+				}
+				if count != 1 {
+					t.Fatalf("la línea %d aparece %d veces", i, count)
+				}
+				if page < lastPage {
+					t.Fatalf("la línea %d está en la página %d, antes que la anterior (%d)", i, page+1, lastPage+1)
+				}
+				if tt.long(i) && !strings.Contains(pages[page], pdfText(fmt.Sprintf("fin %03d", i))) {
+					t.Errorf("la línea %d se partió entre las páginas %d y %d", i, page+1, page+2)
+				}
+				lastPage = page
+				perPage[page]++
+			}
+
+			// Con renglones de 6 mm, una página intermedia de A4 llena la tabla
+			// debajo del encabezado: (297 - 10 - 20 - 6) / 6 = 43 líneas
+			if tt.fullPages {
+				_, pageHeight := gofpdf.New("P", "mm", "A4", "").GetPageSize()
+				margins := tpl.Page.Margins
+				pageSlots := int((pageHeight - margins.Top - margins.Bottom - 6) / 6)
+				for j := 1; j < lastPage; j++ {
+					if perPage[j] != pageSlots {
+						t.Errorf("la página %d tiene %d líneas, se esperaban %d", j+1, perPage[j], pageSlots)
+					}
+				}
+				if want := 1 + (lines-perPage[0]+pageSlots-1)/pageSlots; lastPage+1 != want {
+					t.Errorf("las líneas ocupan %d páginas, se esperaban %d", lastPage+1, want)
+				}
+			}
+		})
+	}
+}
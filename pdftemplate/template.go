@@ -0,0 +1,362 @@
+package pdftemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Tipos de bloque que entiende el renderizador.
+const (
+	BlockText      = "texto"
+	BlockParagraph = "parrafo"
+	BlockRow       = "fila"
+	BlockSpace     = "espacio"
+	BlockServices  = "servicios"
+	BlockSummary   = "resumen"
+	BlockQRCode    = "qr"
+	BlockImage     = "imagen"
+)
+
+var blockTypes = []string{BlockText, BlockParagraph, BlockRow, BlockSpace, BlockServices, BlockSummary, BlockQRCode, BlockImage}
+
+// Template es un diseño declarativo de PDF: la página, las fuentes, las
+// etiquetas fijas y las secciones que se dibujan una debajo de otra. Los
+// textos y las condiciones son plantillas de text/template que se evalúan
+// sobre Data, p. ej. "{{.Labels.debe_a}}" o "eq .Invoice.Status \"borrador\"".
+type Template struct {
+	Page     Page              `json:"pagina"`
+	Fonts    []FontFile        `json:"fuentes"`
+	Font     Font              `json:"fuente"`
+	Labels   map[string]string `json:"etiquetas"`
+	Sections []Section         `json:"secciones"`
+	Footer   *Footer           `json:"pie,omitempty"`
+	// UpdatedAt es la fecha del último cambio de la plantilla guardada; cero en el diseño incluido
+	UpdatedAt time.Time `json:"-"`
+	// FontDir es el directorio donde se buscan los archivos de fuente. No es
+	// parte del diseño: lo fija quien carga la plantilla
+	FontDir string `json:"-"`
+}
+
+// Page describe el papel. Size es un tamaño de gofpdf (A4, Letter, Legal...)
+// y Orientation "P" (vertical) o "L" (horizontal). Las medidas están en milímetros.
+type Page struct {
+	Size        string  `json:"tamano"`
+	Orientation string  `json:"orientacion"`
+	Margins     Margins `json:"margenes"`
+}
+
+// Margins son los márgenes de la página. Bottom es también el punto en que
+// el contenido pasa a una página nueva.
+type Margins struct {
+	Left   float64 `json:"izquierda"`
+	Top    float64 `json:"superior"`
+	Right  float64 `json:"derecha"`
+	Bottom float64 `json:"inferior"`
+}
+
+// FontFile registra un archivo TTF con el nombre de familia y el estilo ("",
+// "B", "I" o "BI") con que lo usan las secciones. File es relativo al directorio de fuentes.
+type FontFile struct {
+	Family string `json:"familia"`
+	Style  string `json:"estilo"`
+	File   string `json:"archivo"`
+}
+
+// Path devuelve la ruta del archivo dentro de dir. Falla si File es una ruta
+// absoluta o sube de directorio con "..", para que una plantilla no pueda
+// leer archivos fuera del directorio de fuentes.
+func (f FontFile) Path(dir string) (string, error) {
+	if !filepath.IsLocal(f.File) {
+		return "", fmt.Errorf("la fuente %q no está dentro del directorio de fuentes", f.File)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(f.File), "/") {
+		if part == ".." {
+			return "", fmt.Errorf("la fuente %q no está dentro del directorio de fuentes", f.File)
+		}
+	}
+	return filepath.Join(dir, f.File), nil
+}
+
+// Font es la fuente de una sección o bloque. Los campos vacíos se heredan; la
+// familia y el estilo se heredan juntos.
+type Font struct {
+	Family string  `json:"familia,omitempty"`
+	Style  string  `json:"estilo,omitempty"`
+	Size   float64 `json:"tamano,omitempty"`
+}
+
+// Footer es el pie que se repite en cada página, centrado en el margen
+// inferior: Text a la izquierda y, a la derecha, la etiqueta "pagina" con
+// {pagina} y {paginas} reemplazados por el número de la página y el total.
+type Footer struct {
+	Text   string  `json:"texto,omitempty"`
+	Font   *Font   `json:"fuente,omitempty"`
+	Height float64 `json:"alto,omitempty"`
+}
+
+// Section agrupa bloques con una fuente común. Si If no se cumple, la sección
+// no se dibuja. SpaceAfter es el espacio que se deja al terminarla.
+type Section struct {
+	Name       string  `json:"nombre"`
+	If         string  `json:"si,omitempty"`
+	Font       *Font   `json:"fuente,omitempty"`
+	SpaceAfter float64 `json:"espacio_despues,omitempty"`
+	Blocks     []Block `json:"bloques"`
+}
+
+// Block es un elemento de una sección. Según Type usa:
+//
+//	texto      Text en una línea de ancho Width (0 hasta el margen), alineada con Align (L, C o R)
+//	parrafo    Text en varias líneas de alto Height
+//	fila       Columns, bloques de texto uno al lado del otro
+//	espacio    un espacio vertical de Height
+//	servicios  tabla con la descripción, la cantidad, el valor unitario y el total de cada línea
+//	           de la factura, de ancho Width y renglones de alto Height; HighlightFont es la fuente
+//	           del encabezado, que se repite en cada página, y DetailFont la del descuento y el IVA
+//	resumen    subtotal, impuestos, retenciones, neto y saldo; HighlightFont resalta el neto y el saldo
+//	qr         el código QR de tamaño Height con Text a su derecha, en DetailFont
+//	imagen     la imagen del perfil indicada en Image (logo o firma) de alto Height, alineada con Align
+//
+// Después de un texto o una fila el cursor baja Advance, que por defecto es
+// Height; después de un párrafo solo baja Advance.
+type Block struct {
+	Type          string  `json:"tipo"`
+	If            string  `json:"si,omitempty"`
+	Text          string  `json:"texto,omitempty"`
+	Image         string  `json:"imagen,omitempty"`
+	Align         string  `json:"alineacion,omitempty"`
+	Width         float64 `json:"ancho,omitempty"`
+	Height        float64 `json:"alto,omitempty"`
+	Advance       float64 `json:"salto,omitempty"`
+	Font          *Font   `json:"fuente,omitempty"`
+	DetailFont    *Font   `json:"fuente_detalle,omitempty"`
+	HighlightFont *Font   `json:"fuente_destacada,omitempty"`
+	Columns       []Block `json:"columnas,omitempty"`
+}
+
+// Label devuelve la etiqueta con esa clave o "" si el diseño no la define.
+func (t Template) Label(key string) string {
+	return t.Labels[key]
+}
+
+// Parse lee un diseño en JSON y lo valida con las fuentes de fontDir: revisa
+// su estructura, que existan las fuentes y que se pueda aplicar a una factura
+// de ejemplo. Es lo que se hace antes de guardar una plantilla. Devuelve un
+// error INVALID_PDF_TEMPLATE con el detalle de cada problema.
+func Parse(layout []byte, fontDir string) (Template, error) {
+	tpl, err := Decode(layout, fontDir)
+	if err != nil {
+		return tpl, err
+	}
+
+	if details := tpl.validate(); len(details) > 0 {
+		return tpl, models.ValidationErrorInit(common.ErrInvalidPDFTemplate, "Revisa el diseño de la plantilla.", details)
+	}
+
+	// Un texto que compila todavía puede fallar con los datos, p. ej. si usa un campo que no existe
+	if err := Render(tpl, sampleData(), io.Discard); err != nil {
+		return tpl, models.ValidationErrorInit(common.ErrInvalidPDFTemplate, "El diseño de la plantilla no se pudo aplicar a una factura de ejemplo.", []models.FieldError{{Field: "diseno", Message: err.Error()}})
+	}
+	return tpl, nil
+}
+
+// Decode lee un diseño en JSON sin validarlo, para las plantillas que ya se
+// validaron al guardarlas. Las etiquetas que el diseño no define se toman del
+// diseño predeterminado.
+func Decode(layout []byte, fontDir string) (Template, error) {
+	tpl := Template{FontDir: fontDir}
+	decoder := json.NewDecoder(bytes.NewReader(layout))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&tpl); err != nil {
+		return tpl, models.ValidationErrorInit(common.ErrInvalidPDFTemplate, "El diseño de la plantilla no es un JSON válido.", []models.FieldError{{Field: "diseno", Message: err.Error()}})
+	}
+
+	labels := map[string]string{}
+	for key, value := range defaultTemplate.Labels {
+		labels[key] = value
+	}
+	for key, value := range tpl.Labels {
+		labels[key] = value
+	}
+	tpl.Labels = labels
+	return tpl, nil
+}
+
+// validate revisa la estructura del diseño, que las fuentes existan y que
+// todos los textos y condiciones compilen.
+func (t Template) validate() []models.FieldError {
+	var details []models.FieldError
+	add := func(field, message string) {
+		details = append(details, models.FieldError{Field: "diseno." + field, Message: message})
+	}
+
+	if t.Page.Orientation != "P" && t.Page.Orientation != "L" {
+		add("pagina.orientacion", "Debe ser P (vertical) o L (horizontal).")
+	}
+	if !isPageSize(t.Page.Size) {
+		add("pagina.tamano", "Usa A3, A4, A5, Letter o Legal.")
+	}
+	margins := t.Page.Margins
+	if margins.Left < 0 || margins.Top < 0 || margins.Right < 0 || margins.Bottom < 0 {
+		add("pagina.margenes", "Los márgenes no pueden ser negativos.")
+	}
+	// Alto útil de la página, entre los márgenes superior e inferior; cero si el papel no es válido
+	var usableHeight float64
+	if isPageSize(t.Page.Size) && (t.Page.Orientation == "P" || t.Page.Orientation == "L") {
+		_, pageHeight := gofpdf.New(t.Page.Orientation, "mm", t.Page.Size, "").GetPageSize()
+		usableHeight = pageHeight - margins.Top - margins.Bottom
+		if usableHeight <= 0 {
+			add("pagina.margenes", "Los márgenes superior e inferior no dejan espacio en la página.")
+		}
+	}
+
+	declared := map[string]bool{}
+	if len(t.Fonts) == 0 {
+		add("fuentes", "Debe registrar al menos una fuente.")
+	}
+	for i, font := range t.Fonts {
+		field := fmt.Sprintf("fuentes[%d]", i)
+		if font.Family == "" {
+			add(field+".familia", "Es obligatoria.")
+		}
+		if !isFontStyle(font.Style) {
+			add(field+".estilo", "Debe ser vacío, B, I o BI.")
+		}
+		if path, err := font.Path(t.FontDir); err != nil {
+			add(field+".archivo", "Debe ser un archivo del directorio de fuentes, sin rutas absolutas ni \"..\".")
+		} else if _, err := os.Stat(path); err != nil {
+			add(field+".archivo", "No se encontró el archivo de fuente "+font.File+".")
+		}
+		declared[font.Family+"|"+font.Style] = true
+	}
+	checkFont := func(field string, font *Font) {
+		if font == nil {
+			return
+		}
+		if font.Size < 0 {
+			add(field+".tamano", "No puede ser negativo.")
+		}
+		if font.Family != "" && !declared[font.Family+"|"+font.Style] {
+			add(field, fmt.Sprintf("La fuente %s con estilo '%s' no está registrada en fuentes.", font.Family, font.Style))
+		}
+	}
+	if t.Font.Family == "" || t.Font.Size <= 0 {
+		add("fuente", "Debe indicar la familia y el tamaño de la fuente principal.")
+	} else {
+		checkFont("fuente", &t.Font)
+	}
+
+	checkTemplate := func(field, text string, condition bool) {
+		if text == "" {
+			return
+		}
+		if condition {
+			text = "{{if " + text + "}}1{{end}}"
+		}
+		if _, err := newTextTemplate(field).Parse(text); err != nil {
+			add(field, "No es una plantilla válida: "+err.Error())
+		}
+	}
+	var checkBlock func(field string, block Block)
+	checkBlock = func(field string, block Block) {
+		if !isBlockType(block.Type) {
+			add(field+".tipo", "Usa "+strings.Join(blockTypes, ", ")+".")
+		}
+		if block.Align != "" && block.Align != "L" && block.Align != "C" && block.Align != "R" {
+			add(field+".alineacion", "Debe ser L, C o R.")
+		}
+		if block.Width < 0 || block.Height < 0 || block.Advance < 0 {
+			add(field, "Las medidas no pueden ser negativas.")
+		}
+		if block.Type == BlockServices && usableHeight > 0 && orDefault(block.Height, 6) > usableHeight {
+			add(field+".alto", fmt.Sprintf("Los renglones de la tabla no pueden medir más que el alto útil de la página (%.0f mm).", usableHeight))
+		}
+		checkTemplate(field+".si", block.If, true)
+		checkTemplate(field+".texto", block.Text, false)
+		checkFont(field+".fuente", block.Font)
+		checkFont(field+".fuente_detalle", block.DetailFont)
+		checkFont(field+".fuente_destacada", block.HighlightFont)
+		if block.Type == BlockImage && block.Image != models.ProfileImageLogo && block.Image != models.ProfileImageSignature {
+			add(field+".imagen", "Usa "+strings.Join(models.ProfileImageKinds, " o ")+".")
+		}
+		if block.Type == BlockRow && len(block.Columns) == 0 {
+			add(field+".columnas", "Una fila debe tener al menos una columna.")
+		}
+		for i, column := range block.Columns {
+			columnField := fmt.Sprintf("%s.columnas[%d]", field, i)
+			if column.Type != "" && column.Type != BlockText {
+				add(columnField+".tipo", "Las columnas de una fila solo pueden ser de tipo texto.")
+				continue
+			}
+			checkBlock(columnField, Block{Type: BlockText, If: column.If, Text: column.Text, Align: column.Align, Width: column.Width, Font: column.Font})
+		}
+	}
+
+	if t.Footer != nil {
+		checkTemplate("pie.texto", t.Footer.Text, false)
+		checkFont("pie.fuente", t.Footer.Font)
+		if t.Footer.Height < 0 {
+			add("pie.alto", "No puede ser negativo.")
+		} else if orDefault(t.Footer.Height, 10) > margins.Bottom {
+			add("pie.alto", "El pie debe caber en el margen inferior de la página.")
+		}
+	}
+
+	if len(t.Sections) == 0 {
+		add("secciones", "Debe tener al menos una sección.")
+	}
+	for i, section := range t.Sections {
+		field := fmt.Sprintf("secciones[%d]", i)
+		checkTemplate(field+".si", section.If, true)
+		checkFont(field+".fuente", section.Font)
+		if section.SpaceAfter < 0 {
+			add(field+".espacio_despues", "No puede ser negativo.")
+		}
+		for j, block := range section.Blocks {
+			checkBlock(fmt.Sprintf("%s.bloques[%d]", field, j), block)
+		}
+	}
+	return details
+}
+
+// newTextTemplate crea una plantilla de texto con las funciones disponibles
+// en los diseños. Las claves que no existen en un mapa producen "".
+func newTextTemplate(name string) *template.Template {
+	return template.New(name).Funcs(textFuncs).Option("missingkey=zero")
+}
+
+func isPageSize(size string) bool {
+	switch size {
+	case "A3", "A4", "A5", "Letter", "Legal":
+		return true
+	}
+	return false
+}
+
+func isFontStyle(style string) bool {
+	switch style {
+	case "", "B", "I", "BI":
+		return true
+	}
+	return false
+}
+
+func isBlockType(blockType string) bool {
+	for _, candidate := range blockTypes {
+		if blockType == candidate {
+			return true
+		}
+	}
+	return false
+}
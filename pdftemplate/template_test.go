@@ -0,0 +1,64 @@
+package pdftemplate
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFontFilePath(t *testing.T) {
+	got, err := FontFile{File: "DejaVuSans.ttf"}.Path("fuentes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join("fuentes", "DejaVuSans.ttf"); got != want {
+		t.Errorf("Path = %q, se esperaba %q", got, want)
+	}
+	if _, err := (FontFile{File: "dejavu/DejaVuSans-Bold.ttf"}).Path("fuentes"); err != nil {
+		t.Errorf("Path con subdirectorio: %v", err)
+	}
+
+	for _, file := range []string{"", "/etc/passwd", "../main.go", "dejavu/../../main.go", "dejavu/.."} {
+		if path, err := (FontFile{File: file}).Path("fuentes"); err == nil {
+			t.Errorf("Path(%q) = %q, se esperaba un error", file, path)
+		}
+	}
+}
+
+func TestValidateRejectsFontsOutsideFontDir(t *testing.T) {
+	tpl := testTemplate(t)
+	tpl.Fonts[0].File = "../pdftemplate/template.go"
+	details := tpl.validate()
+	if len(details) == 0 || details[0].Field != "diseno.fuentes[0].archivo" {
+		t.Fatalf("validate = %v, se esperaba un error en diseno.fuentes[0].archivo", details)
+	}
+
+	// Render tampoco carga la fuente aunque el diseño no se haya validado
+	err := Render(tpl, sampleData(), io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "directorio de fuentes") {
+		t.Fatalf("Render = %v, se esperaba un error del directorio de fuentes", err)
+	}
+}
+
+func TestDecodeDoesNotValidate(t *testing.T) {
+	layout := DefaultLayout()
+	fontDir := filepath.Join("..", "no-existe")
+
+	// Una plantilla guardada se decodifica aunque sus fuentes ya no estén
+	tpl, err := Decode(layout, fontDir)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if tpl.FontDir != fontDir || tpl.Label("debe_a") == "" {
+		t.Errorf("Decode = %+v, se esperaba el directorio de fuentes y las etiquetas predeterminadas", tpl)
+	}
+
+	// Al guardarla, en cambio, Parse revisa que existan
+	if _, err := Parse(layout, fontDir); err == nil {
+		t.Fatal("Parse con fuentes inexistentes no falló")
+	}
+	if _, err := Parse(layout, filepath.Join("..", "font")); err != nil {
+		t.Fatalf("Parse del diseño predeterminado: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type PostgresCatalogRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz CatalogRepository
+var _ interfaces.CatalogRepository = &PostgresCatalogRepository{}
+
+func NewPostgresCatalogRepository(db interfaces.Database) *PostgresCatalogRepository {
+	return &PostgresCatalogRepository{db: db}
+}
+
+const catalogSelect = `SELECT id, usuario_id, descripcion, unidad, valor_unitario, tarifa_iva FROM catalogo_servicios`
+
+func scanCatalogItem(row rowScanner) (models.CatalogItem, error) {
+	var item models.CatalogItem
+	err := row.Scan(&item.ID, &item.UserID, &item.Description, &item.Unit, &item.UnitPrice, &item.IVARate)
+	return item, err
+}
+
+func (r *PostgresCatalogRepository) Create(item *models.CatalogItem) error {
+	err := r.db.QueryRow(`INSERT INTO catalogo_servicios (usuario_id, descripcion, unidad, valor_unitario, tarifa_iva)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		item.UserID, item.Description, item.Unit, item.UnitPrice, item.IVARate).Scan(&item.ID)
+	return catalogConflictError(err, item.Description)
+}
+
+func (r *PostgresCatalogRepository) GetByID(id int64) (models.CatalogItem, error) {
+	return scanCatalogItem(r.db.QueryRow(catalogSelect+` WHERE id = $1`, id))
+}
+
+// List ordena primero los servicios cuya descripción empieza por el texto
+// buscado y luego por descripción.
+func (r *PostgresCatalogRepository) List(filter models.CatalogFilter) ([]models.CatalogItem, error) {
+	where, args := catalogWhere(filter)
+	orderBy := " ORDER BY lower(descripcion), id"
+	if filter.Query != "" {
+		args = append(args, prefixPattern(filter.Query))
+		orderBy = fmt.Sprintf(" ORDER BY (descripcion ILIKE $%d) DESC, lower(descripcion), id", len(args))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`%s%s%s LIMIT $%d OFFSET $%d`, catalogSelect, where, orderBy, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.CatalogItem{}
+	for rows.Next() {
+		item, err := scanCatalogItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *PostgresCatalogRepository) Count(filter models.CatalogFilter) (int, error) {
+	where, args := catalogWhere(filter)
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM catalogo_servicios`+where, args...).Scan(&total)
+	return total, err
+}
+
+// Update modifica el servicio. Las facturas ya hechas conservan los datos que tenían.
+func (r *PostgresCatalogRepository) Update(item models.CatalogItem) (bool, error) {
+	result, err := r.db.Exec(`UPDATE catalogo_servicios SET descripcion = $1, unidad = $2, valor_unitario = $3, tarifa_iva = $4, actualizado_en = NOW()
+		WHERE id = $5`, item.Description, item.Unit, item.UnitPrice, item.IVARate, item.ID)
+	if err != nil {
+		return false, catalogConflictError(err, item.Description)
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// Delete elimina el servicio; las líneas de factura que lo referencian conservan su copia de los datos.
+func (r *PostgresCatalogRepository) Delete(id int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM catalogo_servicios WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// Usage recorre las líneas de las facturas emitidas, enviadas o pagadas que
+// referencian un servicio del catálogo. Los servicios ya eliminados del
+// catálogo aparecen con la descripción de su línea más reciente.
+func (r *PostgresCatalogRepository) Usage(filter models.CatalogUsageFilter) ([]models.CatalogUsage, error) {
+	conditions := []string{`servicio ? 'catalogo_id'`, `f.estado IN ('emitida', 'enviada', 'pagada')`}
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.UserID != 0 {
+		addCondition("f.usuario_id = $%d", filter.UserID)
+	}
+	if filter.DateFrom != "" {
+		addCondition("f.fecha >= $%d::date", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		addCondition("f.fecha < $%d::date + 1", filter.DateTo)
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`WITH lineas AS (
+			SELECT f.id AS factura_id, f.fecha, (servicio->>'catalogo_id')::int AS catalogo_id,
+				servicio->>'descripcion' AS descripcion,
+				COALESCE((servicio->>'cantidad')::numeric, 1) AS cantidad,
+				COALESCE((servicio->>'subtotal')::numeric, 0) AS subtotal
+			FROM facturas f, jsonb_array_elements(f.servicios) AS servicio
+			WHERE %s
+		)
+		SELECT l.catalogo_id,
+			COALESCE(c.descripcion, (array_agg(l.descripcion ORDER BY l.fecha DESC))[1]),
+			COUNT(DISTINCT l.factura_id), COUNT(*), SUM(l.cantidad), SUM(l.subtotal),
+			to_char(MAX(l.fecha), 'YYYY-MM-DD')
+		FROM lineas l
+		LEFT JOIN catalogo_servicios c ON c.id = l.catalogo_id
+		GROUP BY l.catalogo_id, c.descripcion
+		ORDER BY COUNT(DISTINCT l.factura_id) DESC, SUM(l.subtotal) DESC, l.catalogo_id
+		LIMIT $%d`, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []models.CatalogUsage{}
+	for rows.Next() {
+		var item models.CatalogUsage
+		if err := rows.Scan(&item.CatalogID, &item.Description, &item.Invoices, &item.Lines, &item.Quantity, &item.Billed, &item.LastBilled); err != nil {
+			return nil, err
+		}
+		usage = append(usage, item)
+	}
+	return usage, rows.Err()
+}
+
+// catalogWhere arma la cláusula WHERE del listado del catálogo.
+func catalogWhere(filter models.CatalogFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.UserID != 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("usuario_id = $%d", len(args)))
+	}
+	if filter.Query != "" {
+		args = append(args, containsPattern(filter.Query))
+		conditions = append(conditions, fmt.Sprintf("descripcion ILIKE $%d", len(args)))
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// catalogConflictError traduce la violación del índice único de descripción por usuario.
+func catalogConflictError(err error, description string) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return models.ErrorResponseInit(common.ErrCatalogItemAlreadyExists, "Ya tienes un servicio en el catálogo con la descripción '"+description+"'.")
+	}
+	return err
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type PostgresCompanyRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz CompanyRepository
+var _ interfaces.CompanyRepository = &PostgresCompanyRepository{}
+
+func NewPostgresCompanyRepository(db interfaces.Database) *PostgresCompanyRepository {
+	return &PostgresCompanyRepository{db: db}
+}
+
+const companySelect = `SELECT id, usuario_id, nombre, nit, direccion, ciudad, correo, telefono FROM empresas`
+
+func scanCompany(row rowScanner) (models.Company, error) {
+	var company models.Company
+	err := row.Scan(&company.ID, &company.UserID, &company.Name, &company.TIN, &company.Address, &company.City, &company.Email, &company.Phone)
+	return company, err
+}
+
+func (r *PostgresCompanyRepository) Create(company *models.Company) error {
+	err := r.db.QueryRow(`INSERT INTO empresas (usuario_id, nombre, nit, direccion, ciudad, correo, telefono)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		company.UserID, company.Name, company.TIN, company.Address, company.City, company.Email, company.Phone).Scan(&company.ID)
+	return companyConflictError(err, company.TIN)
+}
+
+// insertInvoiceCompany registra en el directorio del dueño, dentro de la
+// transacción de la factura, la empresa de una factura que aún no la tiene. Si
+// entre tanto otra petición registró una empresa con el mismo NIT devuelve
+// COMPANY_ALREADY_EXISTS en vez de duplicarla.
+func insertInvoiceCompany(tx interfaces.Tx, invoice *models.Invoice, ownerID int64) error {
+	if invoice.Company.ID != 0 {
+		return nil
+	}
+	company := invoice.Company
+	err := tx.QueryRow(`INSERT INTO empresas (usuario_id, nombre, nit, direccion, ciudad, correo, telefono)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (usuario_id, (split_part(nit, '-', 1))) DO NOTHING RETURNING id`,
+		ownerID, company.Name, company.TIN, company.Address, company.City, company.Email, company.Phone).Scan(&invoice.Company.ID)
+	if err == sql.ErrNoRows {
+		return models.ErrorResponseInit(common.ErrCompanyAlreadyExists, "Ya tienes una empresa registrada con el NIT "+company.TIN+".")
+	}
+	return err
+}
+
+func (r *PostgresCompanyRepository) GetByID(id int64) (models.Company, error) {
+	return scanCompany(r.db.QueryRow(companySelect+` WHERE id = $1`, id))
+}
+
+func (r *PostgresCompanyRepository) FindByTIN(userID int64, tin string) (models.Company, error) {
+	return scanCompany(r.db.QueryRow(companySelect+` WHERE usuario_id = $1 AND split_part(nit, '-', 1) = split_part($2, '-', 1)`, userID, tin))
+}
+
+// List ordena primero las empresas cuyo nombre o NIT empiezan por el texto
+// buscado y luego por nombre, que es lo que espera un autocompletado.
+func (r *PostgresCompanyRepository) List(filter models.CompanyFilter) ([]models.Company, error) {
+	where, args := companyWhere(filter)
+	orderBy := " ORDER BY lower(nombre), id"
+	if filter.Query != "" {
+		args = append(args, prefixPattern(filter.Query))
+		orderBy = fmt.Sprintf(" ORDER BY (nombre ILIKE $%[1]d OR nit LIKE $%[1]d) DESC, lower(nombre), id", len(args))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`%s%s%s LIMIT $%d OFFSET $%d`, companySelect, where, orderBy, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	companies := []models.Company{}
+	for rows.Next() {
+		company, err := scanCompany(rows)
+		if err != nil {
+			return nil, err
+		}
+		companies = append(companies, company)
+	}
+	return companies, rows.Err()
+}
+
+func (r *PostgresCompanyRepository) Count(filter models.CompanyFilter) (int, error) {
+	where, args := companyWhere(filter)
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM empresas`+where, args...).Scan(&total)
+	return total, err
+}
+
+// Update modifica la empresa. Las facturas ya hechas conservan los datos que tenían.
+func (r *PostgresCompanyRepository) Update(company models.Company) (bool, error) {
+	result, err := r.db.Exec(`UPDATE empresas SET nombre = $1, nit = $2, direccion = $3, ciudad = $4, correo = $5, telefono = $6, actualizado_en = NOW()
+		WHERE id = $7`, company.Name, company.TIN, company.Address, company.City, company.Email, company.Phone, company.ID)
+	if err != nil {
+		return false, companyConflictError(err, company.TIN)
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// Delete elimina la empresa; las facturas que la referencian conservan su copia de los datos.
+func (r *PostgresCompanyRepository) Delete(id int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM empresas WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// companyWhere arma la cláusula WHERE del listado de empresas.
+func companyWhere(filter models.CompanyFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.UserID != 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("usuario_id = $%d", len(args)))
+	}
+	if filter.Query != "" {
+		args = append(args, containsPattern(filter.Query))
+		conditions = append(conditions, fmt.Sprintf("(nombre ILIKE $%[1]d OR replace(nit, '-', '') LIKE replace($%[1]d, '-', ''))", len(args)))
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// prefixPattern convierte un texto en un patrón LIKE que busca los valores
+// que empiezan por el texto literal.
+func prefixPattern(text string) string {
+	return strings.TrimPrefix(containsPattern(text), "%")
+}
+
+// companyConflictError traduce la violación del índice único de NIT por usuario.
+func companyConflictError(err error, tin string) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return models.ErrorResponseInit(common.ErrCompanyAlreadyExists, "Ya tienes una empresa registrada con el NIT "+tin+".")
+	}
+	return err
+}
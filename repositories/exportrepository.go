@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"time"
+)
+
+type PostgresExportRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz ExportRepository
+var _ interfaces.ExportRepository = &PostgresExportRepository{}
+
+func NewPostgresExportRepository(db interfaces.Database) *PostgresExportRepository {
+	return &PostgresExportRepository{db: db}
+}
+
+func (r *PostgresExportRepository) Create(export *models.InvoiceExport) error {
+	filter, err := json.Marshal(export.Filter)
+	if err != nil {
+		return err
+	}
+	return r.db.QueryRow(`INSERT INTO exportaciones (usuario_id, filtro, estado, total) VALUES ($1, $2, $3, $4)
+		RETURNING id, creado_en`, export.UserID, filter, export.Status, export.Total).Scan(&export.ID, &export.CreatedAt)
+}
+
+func (r *PostgresExportRepository) GetByID(id int64) (models.InvoiceExport, error) {
+	var export models.InvoiceExport
+	var filter []byte
+	var finishedAt sql.NullTime
+	err := r.db.QueryRow(`SELECT id, usuario_id, filtro, estado, total, procesadas, error, archivo, creado_en, terminado_en
+		FROM exportaciones WHERE id = $1`, id).
+		Scan(&export.ID, &export.UserID, &filter, &export.Status, &export.Total, &export.Processed, &export.Error, &export.File, &export.CreatedAt, &finishedAt)
+	if err != nil {
+		return export, err
+	}
+	if finishedAt.Valid {
+		export.FinishedAt = &finishedAt.Time
+	}
+	return export, json.Unmarshal(filter, &export.Filter)
+}
+
+func (r *PostgresExportRepository) Start(id int64) error {
+	_, err := r.db.Exec(`UPDATE exportaciones SET estado = $2 WHERE id = $1`, id, models.ExportStatusRunning)
+	return err
+}
+
+func (r *PostgresExportRepository) SetProgress(id int64, processed int) error {
+	_, err := r.db.Exec(`UPDATE exportaciones SET procesadas = $2 WHERE id = $1`, id, processed)
+	return err
+}
+
+func (r *PostgresExportRepository) Finish(export *models.InvoiceExport) error {
+	var finishedAt time.Time
+	err := r.db.QueryRow(`UPDATE exportaciones SET estado = $2, procesadas = $3, error = $4, archivo = $5, terminado_en = NOW()
+		WHERE id = $1 RETURNING terminado_en`, export.ID, export.Status, export.Processed, export.Error, export.File).Scan(&finishedAt)
+	if err != nil {
+		return err
+	}
+	export.FinishedAt = &finishedAt
+	return nil
+}
+
+func (r *PostgresExportRepository) FailInterrupted() (int64, error) {
+	result, err := r.db.Exec(`UPDATE exportaciones SET estado = $1, error = 'La exportación se interrumpió al reiniciar el servidor.', terminado_en = NOW()
+		WHERE estado IN ($2, $3)`, models.ExportStatusFailed, models.ExportStatusPending, models.ExportStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteFinishedOlderThan compara con NOW() de la base de datos, que es
+// también la que fija terminado_en.
+func (r *PostgresExportRepository) DeleteFinishedOlderThan(age time.Duration) ([]string, error) {
+	rows, err := r.db.Query(`DELETE FROM exportaciones WHERE terminado_en < NOW() - $1 * INTERVAL '1 second' RETURNING archivo`, int64(age.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var files []string
+	for rows.Next() {
+		var file string
+		if err := rows.Scan(&file); err != nil {
+			return nil, err
+		}
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, rows.Err()
+}
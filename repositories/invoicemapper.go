@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"encoding/json"
+	"facturaexpress/helpers"
+	"facturaexpress/models"
+	"strings"
+)
+
+// invoiceColumnList enumera, en el mismo orden que invoiceScanTargets, las
+// columnas que se leen de facturas. Cualquier columna nueva se agrega en ambos
+// lugares y todas las consultas la recogen.
+var invoiceColumnList = []string{
+	"id",
+	"nombre_empresa",
+	"nit_empresa",
+	"fecha",
+	"servicios",
+	"valor_total",
+	"nombre_operador",
+	"tipo_documento_operador",
+	"documento_operador",
+	"ciudad_expedicion_documento_operador",
+	"celular_operador",
+	"numero_cuenta_bancaria_operador",
+	"tipo_cuenta_bancaria_operador",
+	"banco_operador",
+	"usuario_id",
+	"retenciones",
+	"impuestos",
+	"subtotal",
+	"total_iva",
+	"total_retenciones",
+	"valor_neto",
+	"numero",
+	"cufe",
+	"estado",
+	"total_notas_credito",
+	"total_notas_debito",
+	"total_pagado",
+	"COALESCE(to_char(fecha_vencimiento, 'YYYY-MM-DD'), '')",
+	"COALESCE(empresa_id, 0)",
+	"direccion_empresa",
+	"ciudad_empresa",
+	"correo_empresa",
+	"telefono_empresa",
+	"actualizado_en",
+}
+
+var invoiceColumns = strings.Join(invoiceColumnList, ", ")
+
+// rowScanner lo cumplen tanto interfaces.Row como interfaces.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// invoiceJSONColumns recibe las columnas JSONB antes de decodificarlas.
+type invoiceJSONColumns struct {
+	services     []byte
+	withholdings []byte
+	taxes        []byte
+}
+
+func invoiceScanTargets(invoice *models.Invoice, jsonColumns *invoiceJSONColumns) []interface{} {
+	return []interface{}{
+		&invoice.ID,
+		&invoice.Company.Name,
+		&invoice.Company.TIN,
+		&invoice.Date,
+		&jsonColumns.services,
+		&invoice.TotalValue,
+		&invoice.Operator.Name,
+		&invoice.Operator.DocumentType,
+		&invoice.Operator.Document,
+		&invoice.Operator.DocumentIssuanceCity,
+		&invoice.Operator.Cellphone,
+		&invoice.Operator.BankAccountNumber,
+		&invoice.Operator.BankAccountType,
+		&invoice.Operator.Bank,
+		&invoice.UserID,
+		&jsonColumns.withholdings,
+		&jsonColumns.taxes,
+		&invoice.Subtotal,
+		&invoice.TotalIVA,
+		&invoice.TotalWithholdings,
+		&invoice.NetPayable,
+		&invoice.Number,
+		&invoice.CUFE,
+		&invoice.Status,
+		&invoice.TotalCredits,
+		&invoice.TotalDebits,
+		&invoice.TotalPaid,
+		&invoice.DueDate,
+		&invoice.Company.ID,
+		&invoice.Company.Address,
+		&invoice.Company.City,
+		&invoice.Company.Email,
+		&invoice.Company.Phone,
+		&invoice.UpdatedAt,
+	}
+}
+
+// scanInvoice es el único mapeo de una fila de facturas a models.Invoice.
+func scanInvoice(row rowScanner) (models.Invoice, error) {
+	var invoice models.Invoice
+	var jsonColumns invoiceJSONColumns
+	if err := row.Scan(invoiceScanTargets(&invoice, &jsonColumns)...); err != nil {
+		return invoice, err
+	}
+
+	services, err := helpers.UnmarshalServices(jsonColumns.services)
+	if err != nil {
+		return invoice, err
+	}
+	invoice.Services = services
+
+	if err := json.Unmarshal(jsonColumns.withholdings, &invoice.Withholdings); err != nil {
+		return invoice, err
+	}
+	if err := json.Unmarshal(jsonColumns.taxes, &invoice.Taxes); err != nil {
+		return invoice, err
+	}
+	invoice.AmountInWords = helpers.AmountInWords(invoice.NetPayable)
+	invoice.ComputeBalance()
+
+	return invoice, nil
+}
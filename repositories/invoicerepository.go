@@ -0,0 +1,445 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"fmt"
+	"strings"
+)
+
+type PostgresInvoiceRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz InvoiceRepository
+var _ interfaces.InvoiceRepository = &PostgresInvoiceRepository{}
+
+func NewPostgresInvoiceRepository(db interfaces.Database) *PostgresInvoiceRepository {
+	return &PostgresInvoiceRepository{db: db}
+}
+
+// Create guarda la factura como borrador. El número y el CUFE se asignan al emitirla.
+// Una empresa que aún no está en el directorio del dueño se registra en la
+// misma transacción.
+func (r *PostgresInvoiceRepository) Create(invoice *models.Invoice) error {
+	servicesJSON, withholdingsJSON, taxesJSON, err := marshalInvoiceJSON(invoice)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertInvoiceCompany(tx, invoice, invoice.UserID); err != nil {
+		return err
+	}
+
+	invoice.Status = models.InvoiceStatusDraft
+	query := `INSERT INTO facturas (nombre_empresa, nit_empresa, fecha, servicios, valor_total, nombre_operador, tipo_documento_operador, documento_operador, ciudad_expedicion_documento_operador, celular_operador, numero_cuenta_bancaria_operador, tipo_cuenta_bancaria_operador, banco_operador, usuario_id, retenciones, impuestos, subtotal, total_iva, total_retenciones, valor_neto, estado, fecha_vencimiento, empresa_id, direccion_empresa, ciudad_empresa, correo_empresa, telefono_empresa) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, NULLIF($22, '')::date, NULLIF($23, 0), $24, $25, $26, $27) RETURNING id`
+	err = tx.QueryRow(query,
+		invoice.Company.Name,
+		invoice.Company.TIN,
+		invoice.Date,
+		servicesJSON,
+		invoice.TotalValue,
+		invoice.Operator.Name,
+		invoice.Operator.DocumentType,
+		invoice.Operator.Document,
+		invoice.Operator.DocumentIssuanceCity,
+		invoice.Operator.Cellphone,
+		invoice.Operator.BankAccountNumber,
+		invoice.Operator.BankAccountType,
+		invoice.Operator.Bank,
+		invoice.UserID,
+		withholdingsJSON,
+		taxesJSON,
+		invoice.Subtotal,
+		invoice.TotalIVA,
+		invoice.TotalWithholdings,
+		invoice.NetPayable,
+		invoice.Status,
+		invoice.DueDate,
+		invoice.Company.ID,
+		invoice.Company.Address,
+		invoice.Company.City,
+		invoice.Company.Email,
+		invoice.Company.Phone).Scan(&invoice.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Issue emite un borrador: le asigna el siguiente número de la numeración del
+// dueño, deja que stamp complete los datos que dependen del número (el CUFE),
+// cambia el estado y registra el evento, todo en una transacción. Si algo
+// falla, el consecutivo no se consume y no quedan huecos.
+func (r *PostgresInvoiceRepository) Issue(id int64, event models.InvoiceEvent, stamp func(invoice *models.Invoice) error) (models.Invoice, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Invoice{}, err
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(tx, id)
+	if err != nil {
+		return invoice, err
+	}
+	if err := models.CheckManualInvoiceTransition(invoice.Status, models.InvoiceStatusIssued); err != nil {
+		return invoice, err
+	}
+
+	numbering, err := lockNumbering(tx, invoice.UserID)
+	if err == sql.ErrNoRows {
+		return invoice, models.ErrorResponseInit(common.ErrNumberingNotConfigured, "Configura la numeración de tu resolución de facturación antes de emitir facturas.")
+	}
+	if err != nil {
+		return invoice, err
+	}
+	consecutive, err := numbering.Next(models.NowInBogota())
+	if err != nil {
+		return invoice, err
+	}
+	if _, err := tx.Exec(`UPDATE numeraciones SET consecutivo_actual = $1 WHERE id = $2`, consecutive, numbering.ID); err != nil {
+		return invoice, err
+	}
+
+	invoice.Number = numbering.FormatNumber(consecutive)
+	if stamp != nil {
+		if err := stamp(&invoice); err != nil {
+			return invoice, err
+		}
+	}
+	if _, err := tx.Exec(`UPDATE facturas SET numero = $1, cufe = $2 WHERE id = $3`, invoice.Number, invoice.CUFE, id); err != nil {
+		return invoice, err
+	}
+
+	event.ToStatus = models.InvoiceStatusIssued
+	if err := changeStatus(tx, &invoice, event); err != nil {
+		return invoice, err
+	}
+	return invoice, tx.Commit()
+}
+
+// ChangeStatus pasa la factura al estado event.ToStatus si la transición es
+// válida y registra el evento.
+func (r *PostgresInvoiceRepository) ChangeStatus(id int64, event models.InvoiceEvent) (models.Invoice, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return models.Invoice{}, err
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(tx, id)
+	if err != nil {
+		return invoice, err
+	}
+	if err := models.CheckManualInvoiceTransition(invoice.Status, event.ToStatus); err != nil {
+		return invoice, err
+	}
+	if err := changeStatus(tx, &invoice, event); err != nil {
+		return invoice, err
+	}
+	return invoice, tx.Commit()
+}
+
+func (r *PostgresInvoiceRepository) ListEvents(invoiceID int64) ([]models.InvoiceEvent, error) {
+	rows, err := r.db.Query(`SELECT id, factura_id, estado_anterior, estado_nuevo, motivo, COALESCE(usuario_id, 0), creado_en
+		FROM factura_eventos WHERE factura_id = $1 ORDER BY creado_en, id`, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.InvoiceEvent{}
+	for rows.Next() {
+		var event models.InvoiceEvent
+		if err := rows.Scan(&event.ID, &event.InvoiceID, &event.FromStatus, &event.ToStatus, &event.Reason, &event.UserID, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// lockInvoice lee la factura bloqueando la fila hasta el fin de la transacción.
+func lockInvoice(tx interfaces.Tx, id int64) (models.Invoice, error) {
+	return scanInvoice(tx.QueryRow(`SELECT `+invoiceColumns+` FROM facturas WHERE id = $1 FOR UPDATE`, id))
+}
+
+// changeStatus actualiza el estado de una factura ya bloqueada y registra el evento.
+func changeStatus(tx interfaces.Tx, invoice *models.Invoice, event models.InvoiceEvent) error {
+	if _, err := tx.Exec(`UPDATE facturas SET estado = $1 WHERE id = $2`, event.ToStatus, invoice.ID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`INSERT INTO factura_eventos (factura_id, estado_anterior, estado_nuevo, motivo, usuario_id) VALUES ($1, $2, $3, $4, NULLIF($5, 0))`,
+		invoice.ID, invoice.Status, event.ToStatus, event.Reason, event.UserID)
+	invoice.Status = event.ToStatus
+	return err
+}
+
+// syncPaidStatus mantiene el estado de una factura ya bloqueada al día con su
+// saldo: la marca como pagada cuando los pagos cubren el valor ajustado y, si
+// estaba pagada y vuelve a tener saldo, le devuelve el estado que tenía antes
+// de pagarse. Las dos transiciones pasan por CheckInvoiceTransition.
+func syncPaidStatus(tx interfaces.Tx, invoice *models.Invoice, userID int64, reason string) error {
+	invoice.ComputeBalance()
+	event := models.InvoiceEvent{InvoiceID: int64(invoice.ID), Reason: reason, UserID: userID}
+
+	if invoice.PaymentStatus == models.PaymentStatusPaid && models.CheckInvoiceTransition(invoice.Status, models.InvoiceStatusPaid) == nil {
+		event.ToStatus = models.InvoiceStatusPaid
+		return changeStatus(tx, invoice, event)
+	}
+	if invoice.Status == models.InvoiceStatusPaid && invoice.Balance > 0 {
+		event.ToStatus = models.InvoiceStatusIssued
+		err := tx.QueryRow(`SELECT estado_anterior FROM factura_eventos WHERE factura_id = $1 AND estado_nuevo = $2 ORDER BY creado_en DESC, id DESC LIMIT 1`,
+			invoice.ID, models.InvoiceStatusPaid).Scan(&event.ToStatus)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if err := models.CheckInvoiceTransition(invoice.Status, event.ToStatus); err != nil {
+			return err
+		}
+		return changeStatus(tx, invoice, event)
+	}
+	return nil
+}
+
+func (r *PostgresInvoiceRepository) GetByID(id int64) (models.Invoice, error) {
+	row := r.db.QueryRow(`SELECT `+invoiceColumns+` FROM facturas WHERE id = $1`, id)
+	return scanInvoice(row)
+}
+
+func (r *PostgresInvoiceRepository) GetOwnerID(id int64) (int64, error) {
+	var ownerID int64
+	err := r.db.QueryRow(`SELECT usuario_id FROM facturas WHERE id = $1`, id).Scan(&ownerID)
+	return ownerID, err
+}
+
+func (r *PostgresInvoiceRepository) GetStatus(id int64) (string, error) {
+	var status string
+	err := r.db.QueryRow(`SELECT estado FROM facturas WHERE id = $1`, id).Scan(&status)
+	return status, err
+}
+
+func (r *PostgresInvoiceRepository) List(filter models.InvoiceFilter) ([]models.Invoice, error) {
+	where, args := invoiceWhere(filter)
+	orderBy, err := invoiceOrderBy(filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM facturas%s%s LIMIT $%d OFFSET $%d`, invoiceColumns, where, orderBy, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invoices []models.Invoice
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	return invoices, rows.Err()
+}
+
+func (r *PostgresInvoiceRepository) Count(filter models.InvoiceFilter) (int, error) {
+	where, args := invoiceWhere(filter)
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM facturas`+where, args...).Scan(&total)
+	return total, err
+}
+
+// Update modifica un borrador. Como Create, registra en la misma transacción
+// la empresa que aún no está en el directorio del dueño de la factura.
+func (r *PostgresInvoiceRepository) Update(id int64, invoice models.Invoice) (bool, error) {
+	servicesJSON, withholdingsJSON, taxesJSON, err := marshalInvoiceJSON(&invoice)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var ownerID int64
+	err = tx.QueryRow(`SELECT usuario_id FROM facturas WHERE id = $1 AND estado = 'borrador' FOR UPDATE`, id).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := insertInvoiceCompany(tx, &invoice, ownerID); err != nil {
+		return false, err
+	}
+
+	query := `UPDATE facturas SET nombre_empresa = $1,nit_empresa = $2,
+			fecha = $3,servicios = $4,
+			valor_total = $5,nombre_operador = $6,
+			tipo_documento_operador = $7,
+			documento_operador = $8,
+			ciudad_expedicion_documento_operador = $9,
+			celular_operador = $10,
+			numero_cuenta_bancaria_operador = $11,
+			tipo_cuenta_bancaria_operador = $12,
+			banco_operador = $13,
+			retenciones = $14,
+			impuestos = $15,
+			subtotal = $16,
+			total_iva = $17,
+			total_retenciones = $18,
+			valor_neto = $19,
+			fecha_vencimiento = NULLIF($21, '')::date,
+			empresa_id = NULLIF($22, 0),
+			direccion_empresa = $23,
+			ciudad_empresa = $24,
+			correo_empresa = $25,
+			telefono_empresa = $26 WHERE id = $20 AND estado = 'borrador'`
+	_, err = tx.Exec(query, invoice.Company.Name, invoice.Company.TIN, invoice.Date, servicesJSON, invoice.TotalValue, invoice.Operator.Name, invoice.Operator.DocumentType, invoice.Operator.Document, invoice.Operator.DocumentIssuanceCity, invoice.Operator.Cellphone, invoice.Operator.BankAccountNumber, invoice.Operator.BankAccountType, invoice.Operator.Bank, withholdingsJSON, taxesJSON, invoice.Subtotal, invoice.TotalIVA, invoice.TotalWithholdings, invoice.NetPayable, id, invoice.DueDate, invoice.Company.ID, invoice.Company.Address, invoice.Company.City, invoice.Company.Email, invoice.Company.Phone)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *PostgresInvoiceRepository) Delete(id int64, ownerID int64) (bool, error) {
+	query := `DELETE FROM facturas WHERE id = $1 AND estado = 'borrador'`
+	args := []interface{}{id}
+	if ownerID != 0 {
+		query += ` AND usuario_id = $2`
+		args = append(args, ownerID)
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// marshalInvoiceJSON serializa las columnas JSONB de la factura. Las listas
+// vacías se guardan como [] y no como null.
+func marshalInvoiceJSON(invoice *models.Invoice) (services, withholdings, taxes []byte, err error) {
+	if services, err = json.Marshal(invoice.Services); err != nil {
+		return nil, nil, nil, err
+	}
+	withholdingList := invoice.Withholdings
+	if withholdingList == nil {
+		withholdingList = []models.Withholding{}
+	}
+	if withholdings, err = json.Marshal(withholdingList); err != nil {
+		return nil, nil, nil, err
+	}
+	taxList := invoice.Taxes
+	if taxList == nil {
+		taxList = []models.TaxLine{}
+	}
+	if taxes, err = json.Marshal(taxList); err != nil {
+		return nil, nil, nil, err
+	}
+	return services, withholdings, taxes, nil
+}
+
+// invoiceSortColumns traduce los campos públicos de ordenamiento a columnas.
+// Solo lo que está en este mapa llega a la consulta.
+var invoiceSortColumns = map[string]string{
+	"id":             "id",
+	"fecha":          "fecha",
+	"valor_total":    "valor_total",
+	"nombre_empresa": "nombre_empresa",
+	"nit_empresa":    "nit_empresa",
+}
+
+// invoiceWhere arma la cláusula WHERE del listado. Los valores del filtro
+// siempre viajan como parámetros; el texto de la consulta solo contiene
+// condiciones fijas.
+func invoiceWhere(filter models.InvoiceFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserID != 0 {
+		addCondition("usuario_id = $%d", filter.UserID)
+	}
+	if filter.DateFrom != "" {
+		addCondition("fecha >= $%d::date", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		addCondition("fecha < $%d::date + 1", filter.DateTo)
+	}
+	if filter.CompanyTIN != "" {
+		addCondition("split_part(replace(nit_empresa, '.', ''), '-', 1) = $%d", filter.CompanyTIN)
+	}
+	if filter.CompanyName != "" {
+		addCondition("nombre_empresa ILIKE $%d", containsPattern(filter.CompanyName))
+	}
+	if filter.OperatorDocument != "" {
+		addCondition("documento_operador = $%d", filter.OperatorDocument)
+	}
+	if filter.MinTotal != nil {
+		addCondition("valor_total >= $%d", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		addCondition("valor_total <= $%d", *filter.MaxTotal)
+	}
+	if filter.Status != "" {
+		addCondition("estado = $%d", filter.Status)
+	}
+	if filter.CompanyID != 0 {
+		addCondition("empresa_id = $%d", filter.CompanyID)
+	}
+	if filter.ServiceText != "" {
+		addCondition("EXISTS (SELECT 1 FROM jsonb_array_elements(servicios) AS servicio WHERE servicio->>'descripcion' ILIKE $%d)", containsPattern(filter.ServiceText))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// invoiceOrderBy arma el ORDER BY; el id se agrega al final para que la paginación sea estable.
+func invoiceOrderBy(sort []models.InvoiceSort) (string, error) {
+	var terms []string
+	for _, term := range sort {
+		column, ok := invoiceSortColumns[term.Field]
+		if !ok {
+			return "", models.ErrorResponseInit(common.ErrInvalidSort, "No se puede ordenar por '"+term.Field+"'.")
+		}
+		direction := "ASC"
+		if term.Descending {
+			direction = "DESC"
+		}
+		terms = append(terms, column+" "+direction)
+		if column == "id" {
+			return " ORDER BY " + strings.Join(terms, ", "), nil
+		}
+	}
+	terms = append(terms, "id ASC")
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// containsPattern convierte un texto en un patrón ILIKE que busca el texto
+// literal, escapando los comodines que pueda traer.
+func containsPattern(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return "%" + replacer.Replace(text) + "%"
+}
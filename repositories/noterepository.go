@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"encoding/json"
+	"facturaexpress/common"
+	"facturaexpress/helpers"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"fmt"
+)
+
+type PostgresNoteRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz NoteRepository
+var _ interfaces.NoteRepository = &PostgresNoteRepository{}
+
+func NewPostgresNoteRepository(db interfaces.Database) *PostgresNoteRepository {
+	return &PostgresNoteRepository{db: db}
+}
+
+// noteSelect lee una nota con la fecha en formato AAAA-MM-DD.
+const noteSelect = `SELECT id, factura_id, tipo, numero, codigo_concepto, descripcion,
+	to_char(fecha, 'YYYY-MM-DD'), servicios, impuestos, subtotal, total_iva, total, usuario_id, creado_en
+	FROM notas`
+
+func scanNote(row rowScanner) (models.Note, error) {
+	var note models.Note
+	var servicesJSON, taxesJSON []byte
+	err := row.Scan(&note.ID, &note.InvoiceID, &note.Type, &note.Number, &note.ReasonCode, &note.Description,
+		&note.Date, &servicesJSON, &taxesJSON, &note.Subtotal, &note.TotalIVA, &note.Total, &note.UserID, &note.CreatedAt)
+	if err != nil {
+		return note, err
+	}
+
+	if note.Services, err = helpers.UnmarshalServices(servicesJSON); err != nil {
+		return note, err
+	}
+	if err := json.Unmarshal(taxesJSON, &note.Taxes); err != nil {
+		return note, err
+	}
+	note.ReasonDescription = models.NoteReasonDescription(note.Type, note.ReasonCode)
+	note.AmountInWords = helpers.AmountInWords(note.Total)
+	return note, nil
+}
+
+// Create bloquea la factura, verifica que admita la nota, toma el siguiente
+// consecutivo del tipo de nota del dueño de la factura, guarda la nota,
+// actualiza los totales de notas de la factura y, si cambia su saldo, su
+// estado de pago, todo en una transacción.
+func (r *PostgresNoteRepository) Create(note *models.Note) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(tx, note.InvoiceID)
+	if err != nil {
+		return err
+	}
+	if err := models.CheckNoteAllowed(invoice.Status); err != nil {
+		return err
+	}
+	// El saldo es el valor ajustado menos lo ya pagado
+	if note.Type == models.NoteTypeCredit && note.Total > invoice.Balance {
+		return models.ErrorResponseInit(common.ErrNoteExceedsBalance, fmt.Sprintf("La nota crédito (%s) supera el saldo de la factura (%s).",
+			note.Total.FormatWithSymbol(models.DefaultCurrency), invoice.Balance.FormatWithSymbol(models.DefaultCurrency)))
+	}
+
+	// Las notas se numeran con el consecutivo del emisor de la factura
+	note.UserID = invoice.UserID
+	var consecutive int64
+	err = tx.QueryRow(`INSERT INTO consecutivos_notas (usuario_id, tipo, ultimo) VALUES ($1, $2, 1)
+		ON CONFLICT (usuario_id, tipo) DO UPDATE SET ultimo = consecutivos_notas.ultimo + 1
+		RETURNING ultimo`, note.UserID, note.Type).Scan(&consecutive)
+	if err != nil {
+		return err
+	}
+	note.Number = note.FormatNumber(consecutive)
+
+	servicesJSON, err := json.Marshal(note.Services)
+	if err != nil {
+		return err
+	}
+	taxList := note.Taxes
+	if taxList == nil {
+		taxList = []models.TaxLine{}
+	}
+	taxesJSON, err := json.Marshal(taxList)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`INSERT INTO notas (factura_id, tipo, consecutivo, numero, codigo_concepto, descripcion, fecha, servicios, impuestos, subtotal, total_iva, total, usuario_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING id, creado_en`,
+		note.InvoiceID, note.Type, consecutive, note.Number, note.ReasonCode, note.Description, note.Date,
+		servicesJSON, taxesJSON, note.Subtotal, note.TotalIVA, note.Total, note.UserID).Scan(&note.ID, &note.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	totalColumn := "total_notas_credito"
+	if note.Type == models.NoteTypeDebit {
+		totalColumn = "total_notas_debito"
+	}
+	if _, err := tx.Exec(`UPDATE facturas SET `+totalColumn+` = `+totalColumn+` + $1 WHERE id = $2`, note.Total, note.InvoiceID); err != nil {
+		return err
+	}
+
+	// Una nota crédito puede saldar una factura con abonos y una nota débito reabrir una pagada
+	if note.Type == models.NoteTypeDebit {
+		invoice.TotalDebits += note.Total
+	} else {
+		invoice.TotalCredits += note.Total
+	}
+	if err := syncPaidStatus(tx, &invoice, note.UserID, note.Title()+" "+note.Number); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresNoteRepository) GetByID(id int64) (models.Note, error) {
+	return scanNote(r.db.QueryRow(noteSelect+` WHERE id = $1`, id))
+}
+
+func (r *PostgresNoteRepository) ListByInvoice(invoiceID int64) ([]models.Note, error) {
+	rows, err := r.db.Query(noteSelect+` WHERE factura_id = $1 ORDER BY fecha, id`, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := []models.Note{}
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"database/sql"
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"fmt"
+)
+
+type PostgresNumberingRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz NumberingRepository
+var _ interfaces.NumberingRepository = &PostgresNumberingRepository{}
+
+func NewPostgresNumberingRepository(db interfaces.Database) *PostgresNumberingRepository {
+	return &PostgresNumberingRepository{db: db}
+}
+
+// numberingSelect lee una numeración con las fechas en formato AAAA-MM-DD.
+const numberingSelect = `SELECT id, usuario_id, prefijo, numero_resolucion,
+	to_char(fecha_resolucion, 'YYYY-MM-DD'), to_char(vigencia_desde, 'YYYY-MM-DD'), to_char(vigencia_hasta, 'YYYY-MM-DD'),
+	rango_desde, rango_hasta, consecutivo_actual
+	FROM numeraciones`
+
+func scanNumbering(row rowScanner) (models.Numbering, error) {
+	var numbering models.Numbering
+	err := row.Scan(&numbering.ID, &numbering.UserID, &numbering.Prefix, &numbering.ResolutionNumber,
+		&numbering.ResolutionDate, &numbering.ValidFrom, &numbering.ValidTo,
+		&numbering.RangeFrom, &numbering.RangeTo, &numbering.Current)
+	return numbering, err
+}
+
+func (r *PostgresNumberingRepository) GetByUserID(userID int64) (models.Numbering, error) {
+	return scanNumbering(r.db.QueryRow(numberingSelect+` WHERE usuario_id = $1`, userID))
+}
+
+// Save crea o reemplaza la numeración del usuario. Si la resolución y el
+// prefijo no cambian conserva el consecutivo guardado; si cambian, la
+// numeración empieza en rango_desde. La fila se lee con el mismo bloqueo que
+// toma Issue, así que un número asignado mientras tanto no se pierde.
+// Devuelve INVALID_NUMBERING si alguno de los números que faltan por asignar
+// ya lo tiene otra factura del usuario, p. ej. de una resolución anterior con
+// el mismo prefijo y un rango que se cruza.
+func (r *PostgresNumberingRepository) Save(numbering *models.Numbering) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, err := lockNumbering(tx, numbering.UserID)
+	switch {
+	case err == nil && current.ResolutionNumber == numbering.ResolutionNumber && current.Prefix == numbering.Prefix:
+		numbering.Current = current.Current
+	case err == nil || err == sql.ErrNoRows:
+		numbering.Current = numbering.RangeFrom - 1
+	default:
+		return err
+	}
+	if err := numbering.Validate(); err != nil {
+		return err
+	}
+	if err := checkNumbersUnused(tx, numbering); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO numeraciones (usuario_id, prefijo, numero_resolucion, fecha_resolucion, vigencia_desde, vigencia_hasta, rango_desde, rango_hasta, consecutivo_actual)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (usuario_id) DO UPDATE SET
+			prefijo = EXCLUDED.prefijo,
+			numero_resolucion = EXCLUDED.numero_resolucion,
+			fecha_resolucion = EXCLUDED.fecha_resolucion,
+			vigencia_desde = EXCLUDED.vigencia_desde,
+			vigencia_hasta = EXCLUDED.vigencia_hasta,
+			rango_desde = EXCLUDED.rango_desde,
+			rango_hasta = EXCLUDED.rango_hasta,
+			consecutivo_actual = EXCLUDED.consecutivo_actual
+		RETURNING id`
+	err = tx.QueryRow(query, numbering.UserID, numbering.Prefix, numbering.ResolutionNumber, numbering.ResolutionDate,
+		numbering.ValidFrom, numbering.ValidTo, numbering.RangeFrom, numbering.RangeTo, numbering.Current).Scan(&numbering.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkNumbersUnused revisa que ninguna factura del usuario tenga ya uno de
+// los números entre Current + 1 y RangeTo con el prefijo de la numeración; si
+// no, Issue chocaría con el índice único de facturas (usuario_id, numero).
+func checkNumbersUnused(tx interfaces.Tx, numbering *models.Numbering) error {
+	var used string
+	err := tx.QueryRow(`SELECT numero FROM facturas
+		WHERE usuario_id = $1 AND numero <> '' AND left(numero, length($2)) = $2
+			AND CASE WHEN substr(numero, length($2) + 1) ~ '^[0-9]{1,18}$'
+				THEN substr(numero, length($2) + 1)::bigint END BETWEEN $3 AND $4
+		ORDER BY id LIMIT 1`, numbering.UserID, numbering.Prefix, numbering.Current+1, numbering.RangeTo).Scan(&used)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return models.ValidationErrorInit(common.ErrInvalidNumbering, "El rango se cruza con números ya asignados.", []models.FieldError{{
+		Field:   "rango_desde",
+		Message: fmt.Sprintf("La factura %s ya usa un número del rango %s a %s; usa otro prefijo o un rango que no se cruce.", used, numbering.FormatNumber(numbering.Current+1), numbering.FormatNumber(numbering.RangeTo)),
+	}})
+}
+
+// lockNumbering lee la numeración del usuario bloqueando la fila hasta el fin
+// de la transacción, de modo que dos facturas simultáneas no reciban el mismo número.
+func lockNumbering(tx interfaces.Tx, userID int64) (models.Numbering, error) {
+	return scanNumbering(tx.QueryRow(numberingSelect+` WHERE usuario_id = $1 FOR UPDATE`, userID))
+}
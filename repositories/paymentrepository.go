@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"fmt"
+)
+
+type PostgresPaymentRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz PaymentRepository
+var _ interfaces.PaymentRepository = &PostgresPaymentRepository{}
+
+func NewPostgresPaymentRepository(db interfaces.Database) *PostgresPaymentRepository {
+	return &PostgresPaymentRepository{db: db}
+}
+
+// paymentSelect lee un movimiento con la fecha en formato AAAA-MM-DD e indica
+// si ya tiene una reversa.
+const paymentSelect = `SELECT id, factura_id, valor, to_char(fecha, 'YYYY-MM-DD'), metodo, referencia, motivo, reversa_de,
+	EXISTS (SELECT 1 FROM pagos AS reversa WHERE reversa.reversa_de = pagos.id), COALESCE(usuario_id, 0), creado_en
+	FROM pagos`
+
+func scanPayment(row rowScanner) (models.Payment, error) {
+	var payment models.Payment
+	err := row.Scan(&payment.ID, &payment.InvoiceID, &payment.Amount, &payment.Date, &payment.Method, &payment.Reference,
+		&payment.Reason, &payment.ReversalOf, &payment.Reversed, &payment.UserID, &payment.CreatedAt)
+	return payment, err
+}
+
+// Create bloquea la factura, verifica que el pago quepa en su saldo, lo
+// registra y actualiza el total pagado y el estado de la factura.
+func (r *PostgresPaymentRepository) Create(payment *models.Payment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(tx, payment.InvoiceID)
+	if err != nil {
+		return err
+	}
+	if err := models.CheckPaymentAllowed(invoice.Status); err != nil {
+		return err
+	}
+	if payment.Amount > invoice.Balance {
+		return models.ErrorResponseInit(common.ErrPaymentExceedsBalance, fmt.Sprintf("El pago (%s) supera el saldo de la factura (%s).",
+			payment.Amount.FormatWithSymbol(models.DefaultCurrency), invoice.Balance.FormatWithSymbol(models.DefaultCurrency)))
+	}
+
+	if err := insertPayment(tx, payment); err != nil {
+		return err
+	}
+	invoice.TotalPaid += payment.Amount
+	if err := syncPaidStatus(tx, &invoice, payment.UserID, paymentEventReason("Pago", payment)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Reverse registra la reversa de un pago. La factura se bloquea antes de
+// comprobar que el pago no se haya reversado, así que dos reversas simultáneas
+// del mismo pago no pasan las dos. Como un pago, una reversa solo se registra
+// si la factura admite pagos: sobre una anulada devuelve PAYMENT_NOT_ALLOWED.
+func (r *PostgresPaymentRepository) Reverse(paymentID int64, reversal *models.Payment) error {
+	original, err := r.GetByID(paymentID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	invoice, err := lockInvoice(tx, original.InvoiceID)
+	if err != nil {
+		return err
+	}
+	if err := models.CheckPaymentAllowed(invoice.Status); err != nil {
+		return err
+	}
+	if original, err = scanPayment(tx.QueryRow(paymentSelect+` WHERE id = $1`, paymentID)); err != nil {
+		return err
+	}
+	if original.ReversalOf != nil {
+		return models.ErrorResponseInit(common.ErrInvalidPayment, "Una reversa no se puede reversar; registra un pago nuevo.")
+	}
+	if original.Reversed {
+		return models.ErrorResponseInit(common.ErrPaymentAlreadyReversed, fmt.Sprintf("El pago %d ya fue reversado.", original.ID))
+	}
+
+	reversal.InvoiceID = original.InvoiceID
+	reversal.Amount = -original.Amount
+	reversal.Method = original.Method
+	reversal.Reference = original.Reference
+	reversal.ReversalOf = &original.ID
+	if err := insertPayment(tx, reversal); err != nil {
+		return err
+	}
+	invoice.TotalPaid += reversal.Amount
+	if err := syncPaidStatus(tx, &invoice, reversal.UserID, paymentEventReason("Reversa del pago", &original)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresPaymentRepository) GetByID(id int64) (models.Payment, error) {
+	return scanPayment(r.db.QueryRow(paymentSelect+` WHERE id = $1`, id))
+}
+
+func (r *PostgresPaymentRepository) ListByInvoice(invoiceID int64) ([]models.Payment, error) {
+	rows, err := r.db.Query(paymentSelect+` WHERE factura_id = $1 ORDER BY fecha, id`, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []models.Payment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+// insertPayment guarda el movimiento y suma su valor al total pagado de la factura.
+func insertPayment(tx interfaces.Tx, payment *models.Payment) error {
+	err := tx.QueryRow(`INSERT INTO pagos (factura_id, valor, fecha, metodo, referencia, motivo, reversa_de, usuario_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, 0)) RETURNING id, creado_en`,
+		payment.InvoiceID, payment.Amount, payment.Date, payment.Method, payment.Reference, payment.Reason, payment.ReversalOf, payment.UserID).
+		Scan(&payment.ID, &payment.CreatedAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE facturas SET total_pagado = total_pagado + $1 WHERE id = $2`, payment.Amount, payment.InvoiceID)
+	return err
+}
+
+// paymentEventReason describe el movimiento en el historial de la factura, p. ej. "Pago 12 (transferencia 98765)".
+func paymentEventReason(label string, payment *models.Payment) string {
+	reason := fmt.Sprintf("%s %d (%s", label, payment.ID, payment.Method)
+	if payment.Reference != "" {
+		reason += " " + payment.Reference
+	}
+	return reason + ")"
+}
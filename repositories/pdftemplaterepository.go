@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+
+	"github.com/lib/pq"
+)
+
+type PostgresPDFTemplateRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz PDFTemplateRepository
+var _ interfaces.PDFTemplateRepository = &PostgresPDFTemplateRepository{}
+
+func NewPostgresPDFTemplateRepository(db interfaces.Database) *PostgresPDFTemplateRepository {
+	return &PostgresPDFTemplateRepository{db: db}
+}
+
+const pdfTemplateSelect = `SELECT id, nombre, descripcion, predeterminada, diseno, actualizado_en FROM plantillas_pdf`
+
+func scanPDFTemplate(row rowScanner) (models.PDFTemplate, error) {
+	var template models.PDFTemplate
+	var layout []byte
+	err := row.Scan(&template.ID, &template.Name, &template.Description, &template.Default, &layout, &template.UpdatedAt)
+	template.Layout = layout
+	return template, err
+}
+
+func (r *PostgresPDFTemplateRepository) Create(template *models.PDFTemplate) error {
+	return r.save(func(tx interfaces.Tx) error {
+		return tx.QueryRow(`INSERT INTO plantillas_pdf (nombre, descripcion, diseno, predeterminada) VALUES ($1, $2, $3, $4) RETURNING id`,
+			template.Name, template.Description, []byte(template.Layout), template.Default).Scan(&template.ID)
+	}, template.Default, template.Name)
+}
+
+func (r *PostgresPDFTemplateRepository) GetByID(id int64) (models.PDFTemplate, error) {
+	return scanPDFTemplate(r.db.QueryRow(pdfTemplateSelect+` WHERE id = $1`, id))
+}
+
+func (r *PostgresPDFTemplateRepository) List() ([]models.PDFTemplate, error) {
+	rows, err := r.db.Query(pdfTemplateSelect + ` ORDER BY predeterminada DESC, lower(nombre), id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.PDFTemplate{}
+	for rows.Next() {
+		template, err := scanPDFTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+func (r *PostgresPDFTemplateRepository) Update(template models.PDFTemplate) (bool, error) {
+	updated := false
+	err := r.save(func(tx interfaces.Tx) error {
+		result, err := tx.Exec(`UPDATE plantillas_pdf SET nombre = $1, descripcion = $2, diseno = $3, predeterminada = $4, actualizado_en = NOW()
+			WHERE id = $5`, template.Name, template.Description, []byte(template.Layout), template.Default, template.ID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		updated = rowsAffected > 0
+		return err
+	}, template.Default, template.Name)
+	return updated, err
+}
+
+// Delete elimina la plantilla; los usuarios que la tenían asignada vuelven a la predeterminada.
+func (r *PostgresPDFTemplateRepository) Delete(id int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM plantillas_pdf WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+func (r *PostgresPDFTemplateRepository) ForUser(userID int64) (models.PDFTemplate, error) {
+	return scanPDFTemplate(r.db.QueryRow(pdfTemplateSelect+`
+		WHERE id = (SELECT plantilla_pdf_id FROM usuarios WHERE id = $1) OR predeterminada
+		ORDER BY predeterminada
+		LIMIT 1`, userID))
+}
+
+func (r *PostgresPDFTemplateRepository) AssignToUser(userID int64, templateID *int64) (bool, error) {
+	result, err := r.db.Exec(`UPDATE usuarios SET plantilla_pdf_id = $1 WHERE id = $2`, templateID, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// save ejecuta write en una transacción. Si la plantilla queda como
+// predeterminada, antes desmarca la anterior.
+func (r *PostgresPDFTemplateRepository) save(write func(tx interfaces.Tx) error, isDefault bool, name string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if isDefault {
+		if _, err := tx.Exec(`UPDATE plantillas_pdf SET predeterminada = FALSE WHERE predeterminada`); err != nil {
+			return err
+		}
+	}
+	if err := write(tx); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return models.ErrorResponseInit(common.ErrPDFTemplateAlreadyExists, "Ya existe una plantilla llamada '"+name+"'.")
+		}
+		return err
+	}
+	return tx.Commit()
+}
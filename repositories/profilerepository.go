@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"database/sql"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+
+	"github.com/lib/pq"
+)
+
+type PostgresProfileRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz ProfileRepository
+var _ interfaces.ProfileRepository = &PostgresProfileRepository{}
+
+func NewPostgresProfileRepository(db interfaces.Database) *PostgresProfileRepository {
+	return &PostgresProfileRepository{db: db}
+}
+
+func (r *PostgresProfileRepository) GetByUserID(userID int64) (models.OperatorProfile, error) {
+	profile := models.OperatorProfile{UserID: userID, BankAccounts: []models.BankAccount{}}
+	err := r.db.QueryRow(`SELECT nombre, tipo_documento, documento, ciudad_expedicion_documento, celular
+		FROM perfiles_operador WHERE usuario_id = $1`, userID).
+		Scan(&profile.Name, &profile.DocumentType, &profile.Document, &profile.DocumentIssuanceCity, &profile.Cellphone)
+	if err != nil && err != sql.ErrNoRows {
+		return profile, err
+	}
+
+	rows, err := r.db.Query(`SELECT id, numero, tipo, banco, predeterminada FROM cuentas_bancarias
+		WHERE usuario_id = $1 ORDER BY predeterminada DESC, id`, userID)
+	if err != nil {
+		return profile, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var account models.BankAccount
+		if err := rows.Scan(&account.ID, &account.Number, &account.Type, &account.Bank, &account.Default); err != nil {
+			return profile, err
+		}
+		profile.BankAccounts = append(profile.BankAccounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return profile, err
+	}
+
+	profile.Images, err = r.queryImages(`SELECT tipo, tipo_contenido, octet_length(contenido), NULL, actualizado_en
+		FROM imagenes_perfil WHERE usuario_id = $1 ORDER BY tipo DESC`, userID)
+	return profile, err
+}
+
+// Save guarda el perfil y reemplaza las cuentas en una transacción. Las
+// cuentas que conservan su número conservan también su id.
+func (r *PostgresProfileRepository) Save(profile *models.OperatorProfile) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO perfiles_operador (usuario_id, nombre, tipo_documento, documento, ciudad_expedicion_documento, celular)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (usuario_id) DO UPDATE SET nombre = EXCLUDED.nombre, tipo_documento = EXCLUDED.tipo_documento,
+			documento = EXCLUDED.documento, ciudad_expedicion_documento = EXCLUDED.ciudad_expedicion_documento,
+			celular = EXCLUDED.celular, actualizado_en = NOW()`,
+		profile.UserID, profile.Name, profile.DocumentType, profile.Document, profile.DocumentIssuanceCity, profile.Cellphone)
+	if err != nil {
+		return err
+	}
+
+	// Primero se quita la marca de predeterminada para no chocar con el índice único
+	numbers := make([]string, 0, len(profile.BankAccounts))
+	for _, account := range profile.BankAccounts {
+		numbers = append(numbers, account.Number)
+	}
+	if _, err := tx.Exec(`UPDATE cuentas_bancarias SET predeterminada = FALSE WHERE usuario_id = $1`, profile.UserID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM cuentas_bancarias WHERE usuario_id = $1 AND NOT (numero = ANY($2))`, profile.UserID, pq.Array(numbers)); err != nil {
+		return err
+	}
+	for i := range profile.BankAccounts {
+		account := &profile.BankAccounts[i]
+		err := tx.QueryRow(`INSERT INTO cuentas_bancarias (usuario_id, numero, tipo, banco, predeterminada) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (usuario_id, numero) DO UPDATE SET tipo = EXCLUDED.tipo, banco = EXCLUDED.banco, predeterminada = EXCLUDED.predeterminada
+			RETURNING id`, profile.UserID, account.Number, account.Type, account.Bank, account.Default).Scan(&account.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresProfileRepository) Images(userID int64) ([]models.ProfileImage, error) {
+	return r.queryImages(`SELECT tipo, tipo_contenido, octet_length(contenido), contenido, actualizado_en
+		FROM imagenes_perfil WHERE usuario_id = $1 ORDER BY tipo DESC`, userID)
+}
+
+func (r *PostgresProfileRepository) GetImage(userID int64, kind string) (models.ProfileImage, error) {
+	images, err := r.queryImages(`SELECT tipo, tipo_contenido, octet_length(contenido), contenido, actualizado_en
+		FROM imagenes_perfil WHERE usuario_id = $1 AND tipo = $2`, userID, kind)
+	if err != nil {
+		return models.ProfileImage{}, err
+	}
+	if len(images) == 0 {
+		return models.ProfileImage{}, sql.ErrNoRows
+	}
+	return images[0], nil
+}
+
+func (r *PostgresProfileRepository) SaveImage(image *models.ProfileImage) error {
+	image.Size = len(image.Content)
+	return r.db.QueryRow(`INSERT INTO imagenes_perfil (usuario_id, tipo, tipo_contenido, contenido) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (usuario_id, tipo) DO UPDATE SET tipo_contenido = EXCLUDED.tipo_contenido, contenido = EXCLUDED.contenido,
+			actualizado_en = NOW()
+		RETURNING actualizado_en`, image.UserID, image.Kind, image.ContentType, image.Content).Scan(&image.UpdatedAt)
+}
+
+func (r *PostgresProfileRepository) DeleteImage(userID int64, kind string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM imagenes_perfil WHERE usuario_id = $1 AND tipo = $2`, userID, kind)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+// queryImages lee imágenes de un usuario con las columnas tipo,
+// tipo_contenido, tamaño, contenido (NULL para no traerlo) y actualizado_en.
+// El primer parámetro de la consulta es siempre el usuario.
+func (r *PostgresProfileRepository) queryImages(query string, userID int64, args ...interface{}) ([]models.ProfileImage, error) {
+	rows, err := r.db.Query(query, append([]interface{}{userID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	images := []models.ProfileImage{}
+	for rows.Next() {
+		image := models.ProfileImage{UserID: userID}
+		if err := rows.Scan(&image.Kind, &image.ContentType, &image.Size, &image.Content, &image.UpdatedAt); err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+	"fmt"
+)
+
+type PostgresReportRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz ReportRepository
+var _ interfaces.ReportRepository = &PostgresReportRepository{}
+
+func NewPostgresReportRepository(db interfaces.Database) *PostgresReportRepository {
+	return &PostgresReportRepository{db: db}
+}
+
+// agingBasisColumns traduce la base del informe a la fecha desde la que se
+// cuentan los días. Solo lo que está en este mapa llega a la consulta.
+var agingBasisColumns = map[string]string{
+	models.AgingBasisDate:    "fecha::date",
+	models.AgingBasisDueDate: "COALESCE(fecha_vencimiento, fecha::date)",
+}
+
+// Aging calcula el saldo de cada factura a la fecha de corte (valor neto
+// ajustado por las notas menos los pagos, contando solo las notas y los pagos
+// con fecha hasta el corte) y lo suma por NIT en el rango de días que le
+// corresponde. Las facturas con fecha posterior al corte no entran.
+func (r *PostgresReportRepository) Aging(filter models.AgingFilter) ([]models.AgingRow, error) {
+	basis, ok := agingBasisColumns[filter.Basis]
+	if !ok {
+		basis = agingBasisColumns[models.AgingBasisDueDate]
+	}
+
+	where, args := invoiceWhere(models.InvoiceFilter{UserID: filter.UserID, OperatorDocument: filter.OperatorDocument})
+	if where == "" {
+		where = " WHERE "
+	} else {
+		where += " AND "
+	}
+	args = append(args, filter.AsOf)
+	cutoff := fmt.Sprintf("$%d::date", len(args))
+	where += "estado IN ('emitida', 'enviada', 'pagada') AND fecha::date <= " + cutoff
+
+	query := fmt.Sprintf(`WITH saldos AS (
+			SELECT split_part(replace(nit_empresa, '.', ''), '-', 1) AS nit, nombre_empresa,
+				valor_neto
+					+ COALESCE((SELECT SUM(CASE WHEN n.tipo = 'debito' THEN n.total ELSE -n.total END)
+						FROM notas n WHERE n.factura_id = facturas.id AND n.fecha <= %[1]s), 0)
+					- COALESCE((SELECT SUM(p.valor) FROM pagos p WHERE p.factura_id = facturas.id AND p.fecha <= %[1]s), 0) AS saldo,
+				%[1]s - %[2]s AS dias
+			FROM facturas%[3]s
+		)
+		SELECT nit, MAX(nombre_empresa), COUNT(*),
+			COALESCE(SUM(saldo) FILTER (WHERE dias <= 30), 0),
+			COALESCE(SUM(saldo) FILTER (WHERE dias BETWEEN 31 AND 60), 0),
+			COALESCE(SUM(saldo) FILTER (WHERE dias BETWEEN 61 AND 90), 0),
+			COALESCE(SUM(saldo) FILTER (WHERE dias > 90), 0),
+			SUM(saldo)
+		FROM saldos
+		WHERE saldo > 0
+		GROUP BY nit
+		ORDER BY SUM(saldo) DESC, nit`, cutoff, basis, where)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := []models.AgingRow{}
+	for rows.Next() {
+		var row models.AgingRow
+		if err := rows.Scan(&row.CompanyTIN, &row.CompanyName, &row.Invoices,
+			&row.Days0To30, &row.Days31To60, &row.Days61To90, &row.Over90, &row.Total); err != nil {
+			return nil, err
+		}
+		report = append(report, row)
+	}
+	return report, rows.Err()
+}
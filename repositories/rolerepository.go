@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+)
+
+type PostgresRoleRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz RoleRepository
+var _ interfaces.RoleRepository = &PostgresRoleRepository{}
+
+func NewPostgresRoleRepository(db interfaces.Database) *PostgresRoleRepository {
+	return &PostgresRoleRepository{db: db}
+}
+
+func (r *PostgresRoleRepository) List() ([]models.Role, error) {
+	rows, err := r.db.Query(`SELECT id, name FROM roles ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []models.Role
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(&role.ID, &role.Name); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *PostgresRoleRepository) Exists(id int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM roles WHERE id = $1)`, id).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresRoleRepository) GetIDByName(name string) (int64, error) {
+	var roleID int64
+	err := r.db.QueryRow(`SELECT id FROM roles WHERE name = $1`, name).Scan(&roleID)
+	return roleID, err
+}
+
+func (r *PostgresRoleRepository) UserHasRole(userID int, roleID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM user_roles WHERE user_id = $1 AND role_id = $2)`, userID, roleID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresRoleRepository) UserHasRoleName(userID int64, name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM user_roles JOIN roles ON user_roles.role_id = roles.id WHERE user_roles.user_id = $1 AND roles.name = $2)`, userID, name).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresRoleRepository) AssignToUser(userID int64, roleID int64) error {
+	_, err := r.db.Exec(`INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2)`, userID, roleID)
+	return err
+}
+
+func (r *PostgresRoleRepository) UpdateUserRole(userID int, roleID int) error {
+	_, err := r.db.Exec(`UPDATE user_roles SET role_id = $1 WHERE user_id = $2`, roleID, userID)
+	return err
+}
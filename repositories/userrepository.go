@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"database/sql"
+	"facturaexpress/interfaces"
+	"facturaexpress/models"
+)
+
+type PostgresUserRepository struct {
+	db interfaces.Database
+}
+
+// implemento la interfaz UserRepository
+var _ interfaces.UserRepository = &PostgresUserRepository{}
+
+func NewPostgresUserRepository(db interfaces.Database) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+// userWithRoleQuery une el usuario con su rol; se completa con la condición WHERE.
+const userWithRoleQuery = `SELECT usuarios.id, usuarios.nombre_usuario, usuarios.correo, roles.name
+	FROM usuarios
+	INNER JOIN user_roles ON usuarios.id = user_roles.user_id
+	INNER JOIN roles ON user_roles.role_id = roles.id`
+
+func (r *PostgresUserRepository) Create(username string, hashedPassword []byte, email string) (int64, error) {
+	var userID int64
+	err := r.db.QueryRow(`INSERT INTO usuarios (nombre_usuario, password, correo) VALUES ($1, $2, $3) RETURNING id`, username, hashedPassword, email).Scan(&userID)
+	return userID, err
+}
+
+func (r *PostgresUserRepository) Exists(id int64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM usuarios WHERE id = $1)`, id).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresUserRepository) ExistsByUsernameOrEmail(username string, email string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM usuarios WHERE nombre_usuario = $1 OR correo = $2)`, username, email).Scan(&exists)
+	return exists, err
+}
+
+func (r *PostgresUserRepository) FindByEmail(email string) (models.User, error) {
+	var user models.User
+	err := r.db.QueryRow(`SELECT usuarios.id, usuarios.nombre_usuario, usuarios.password, usuarios.correo, roles.name
+	FROM usuarios
+	INNER JOIN user_roles ON usuarios.id = user_roles.user_id
+	INNER JOIN roles ON user_roles.role_id = roles.id
+	WHERE usuarios.correo = $1`, email).Scan(&user.ID, &user.Username, &user.Password, &user.Email, &user.Role)
+	return user, err
+}
+
+func (r *PostgresUserRepository) GetByID(id int64) (models.User, error) {
+	var user models.User
+	err := r.db.QueryRow(userWithRoleQuery+` WHERE usuarios.id = $1`, id).Scan(&user.ID, &user.Username, &user.Email, &user.Role)
+	return user, err
+}
+
+func (r *PostgresUserRepository) List() ([]models.User, error) {
+	rows, err := r.db.Query(userWithRoleQuery + ` ORDER BY usuarios.id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.Role); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *PostgresUserRepository) Update(id int64, username string, hashedPassword []byte, email string) (bool, error) {
+	result, err := r.db.Exec(`UPDATE usuarios SET nombre_usuario = $1, password = $2, correo = $3 WHERE id = $4`, username, hashedPassword, email, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+func (r *PostgresUserRepository) Delete(id int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM usuarios WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+func (r *PostgresUserRepository) SaveToken(userID int64, token string) error {
+	_, err := r.db.Exec(`UPDATE usuarios SET jwt_token = $1 WHERE id = $2`, token, userID)
+	return err
+}
+
+func (r *PostgresUserRepository) GetToken(userID int64) (string, error) {
+	var token sql.NullString
+	err := r.db.QueryRow(`SELECT jwt_token FROM usuarios WHERE id = $1`, userID).Scan(&token)
+	return token.String, err
+}
+
+func (r *PostgresUserRepository) IsTokenBlacklisted(token string) (bool, error) {
+	var blacklisted bool
+	err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM jwt_blacklist WHERE token = $1)`, token).Scan(&blacklisted)
+	return blacklisted, err
+}
+
+func (r *PostgresUserRepository) BlacklistToken(token string) error {
+	_, err := r.db.Exec(`INSERT INTO jwt_blacklist (token) VALUES ($1) ON CONFLICT (token) DO NOTHING`, token)
+	return err
+}
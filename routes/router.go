@@ -3,16 +3,77 @@ package routes
 import (
 	"facturaexpress/common"
 	authHandler "facturaexpress/handlers/auth"
+	catalogHandler "facturaexpress/handlers/catalog"
+	companyHandler "facturaexpress/handlers/company"
 	invoiceHandler "facturaexpress/handlers/invoice"
+	noteHandler "facturaexpress/handlers/note"
+	numberingHandler "facturaexpress/handlers/numbering"
+	paymentHandler "facturaexpress/handlers/payment"
+	pdfTemplateHandler "facturaexpress/handlers/pdftemplate"
+	reportHandler "facturaexpress/handlers/report"
 	roleHandler "facturaexpress/handlers/role"
 	userHandler "facturaexpress/handlers/user"
+	"facturaexpress/interfaces"
 	middleware "facturaexpress/middlewares"
+	"facturaexpress/pdftemplate"
+	"facturaexpress/taxes"
+	"facturaexpress/ubl"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter(jwtKey []byte, expTimeStr string) *gin.Engine {
+// Dependencies agrupa los servicios que main construye una sola vez y que el
+// enrutador reparte entre los controladores.
+type Dependencies struct {
+	Invoices     interfaces.InvoiceRepository
+	Companies    interfaces.CompanyRepository
+	Catalog      interfaces.CatalogRepository
+	Numberings   interfaces.NumberingRepository
+	Notes        interfaces.NoteRepository
+	Payments     interfaces.PaymentRepository
+	Reports      interfaces.ReportRepository
+	Users        interfaces.UserRepository
+	Profiles     interfaces.ProfileRepository
+	PDFTemplates interfaces.PDFTemplateRepository
+	PDFCache     *pdftemplate.Cache
+	FontDir      string
+	Exports      interfaces.ExportRepository
+	ExportDir    string
+	Roles        interfaces.RoleRepository
+	JWTKey       []byte
+	ExpTimeStr   string
+	TaxRates     taxes.Rates
+	DIAN         ubl.Settings
+}
+
+func NewRouter(deps Dependencies) *gin.Engine {
+	authHandlers := authHandler.NewAuthHandler(deps.Users, deps.Roles, deps.JWTKey, deps.ExpTimeStr)
+	companyHandlers := companyHandler.NewCompanyHandler(deps.Companies)
+	catalogHandlers := catalogHandler.NewCatalogHandler(deps.Catalog, deps.TaxRates)
+	invoiceHandlers := invoiceHandler.NewInvoiceHandler(invoiceHandler.Dependencies{
+		Invoices:   deps.Invoices,
+		Companies:  deps.Companies,
+		Catalog:    deps.Catalog,
+		Users:      deps.Users,
+		Profiles:   deps.Profiles,
+		Numberings: deps.Numberings,
+		Templates:  deps.PDFTemplates,
+		PDFCache:   deps.PDFCache,
+		FontDir:    deps.FontDir,
+		Exports:    deps.Exports,
+		ExportDir:  deps.ExportDir,
+		TaxRates:   deps.TaxRates,
+		DIAN:       deps.DIAN,
+	})
+	noteHandlers := noteHandler.NewNoteHandler(deps.Notes, deps.Invoices, deps.PDFTemplates, deps.FontDir, deps.TaxRates)
+	numberingHandlers := numberingHandler.NewNumberingHandler(deps.Numberings)
+	paymentHandlers := paymentHandler.NewPaymentHandler(deps.Payments, deps.Invoices)
+	pdfTemplateHandlers := pdfTemplateHandler.NewPDFTemplateHandler(deps.PDFTemplates, deps.FontDir)
+	reportHandlers := reportHandler.NewReportHandler(deps.Reports)
+	roleHandlers := roleHandler.NewRoleHandler(deps.Users, deps.Roles)
+	userHandlers := userHandler.NewUserHandler(deps.Users, deps.Profiles)
+
 	router := gin.Default()
 	router.ForwardedByClientIP = true
 	router.SetTrustedProxies([]string{"192.168.1.2", "10.0.0.0/8"})
@@ -26,76 +87,219 @@ func NewRouter(jwtKey []byte, expTimeStr string) *gin.Engine {
 	v1 := router.Group("/v1")
 	{
 		v1.POST("/register", func(context *gin.Context) {
-			authHandler.Register(context)
+			authHandlers.Register(context)
 		})
 
 		v1.POST("/login", func(context *gin.Context) {
-			authHandler.Login(context, jwtKey, expTimeStr)
+			authHandlers.Login(context)
 		})
 
 		// Routes protected with AuthMiddleware middleware
 		authorized := v1.Group("/")
 		authorized.Use(func(context *gin.Context) {
-			middleware.AuthMiddleware(context, jwtKey)
+			middleware.AuthMiddleware(context, deps.Users, deps.JWTKey)
 		})
 		{
 			adminRoutes := authorized.Group("/")
 			adminRoutes.Use(func(context *gin.Context) {
-				middleware.RoleAuthMiddleware(context, common.ADMIN)
+				middleware.RoleAuthMiddleware(context, deps.Roles, common.ADMIN)
 			})
 
 			adminRoutes.PUT("/users/:id/new-role/:newRoleID", func(context *gin.Context) {
-				roleHandler.AssignRole(context)
+				roleHandlers.AssignRole(context)
 			})
 			adminRoutes.PUT("/users/:id/roles/:roleID", func(context *gin.Context) {
-				roleHandler.UpdateRole(context)
+				roleHandlers.UpdateRole(context)
 			})
 
 			adminRoutes.GET("/roles", func(context *gin.Context) {
-				roleHandler.ListRoles(context)
+				roleHandlers.ListRoles(context)
 			})
 
 			adminRoutes.GET("/users", func(context *gin.Context) {
-				userHandler.ListUsers(context)
+				userHandlers.ListUsers(context)
 			})
 			adminRoutes.POST("/users", func(context *gin.Context) {
-				userHandler.CreateUser(context)
+				userHandlers.CreateUser(context)
 			})
 			adminRoutes.PUT("/users/:id", func(context *gin.Context) {
-				userHandler.UpdateUser(context)
+				userHandlers.UpdateUser(context)
 			})
 			adminRoutes.DELETE("/users/:id", func(context *gin.Context) {
-				userHandler.DeleteUser(context)
+				userHandlers.DeleteUser(context)
+			})
+			adminRoutes.PUT("/users/:id/pdf-template", func(context *gin.Context) {
+				pdfTemplateHandlers.AssignPDFTemplate(context)
+			})
+
+			adminRoutes.GET("/pdf-templates", func(context *gin.Context) {
+				pdfTemplateHandlers.ListPDFTemplates(context)
+			})
+			adminRoutes.GET("/pdf-templates/default", func(context *gin.Context) {
+				pdfTemplateHandlers.GetDefaultPDFTemplate(context)
+			})
+			adminRoutes.GET("/pdf-templates/:id", func(context *gin.Context) {
+				pdfTemplateHandlers.GetPDFTemplate(context)
+			})
+			adminRoutes.POST("/pdf-templates", func(context *gin.Context) {
+				pdfTemplateHandlers.CreatePDFTemplate(context)
+			})
+			adminRoutes.PUT("/pdf-templates/:id", func(context *gin.Context) {
+				pdfTemplateHandlers.UpdatePDFTemplate(context)
+			})
+			adminRoutes.DELETE("/pdf-templates/:id", func(context *gin.Context) {
+				pdfTemplateHandlers.DeletePDFTemplate(context)
 			})
 
 			authorized.GET("/user/profile", func(context *gin.Context) {
-				userHandler.GetUserInfo(context)
+				userHandlers.GetUserInfo(context)
+			})
+			authorized.PUT("/user/profile", func(context *gin.Context) {
+				userHandlers.UpdateProfile(context)
+			})
+			authorized.GET("/user/profile/images/:tipo", func(context *gin.Context) {
+				userHandlers.GetProfileImage(context)
+			})
+			authorized.PUT("/user/profile/images/:tipo", func(context *gin.Context) {
+				userHandlers.UploadProfileImage(context)
+			})
+			authorized.DELETE("/user/profile/images/:tipo", func(context *gin.Context) {
+				userHandlers.DeleteProfileImage(context)
+			})
+
+			authorized.GET("/numbering", func(context *gin.Context) {
+				numberingHandlers.GetNumbering(context)
+			})
+			authorized.PUT("/numbering", func(context *gin.Context) {
+				numberingHandlers.UpdateNumbering(context)
+			})
+
+			authorized.GET("/companies", func(context *gin.Context) {
+				companyHandlers.ListCompanies(context)
+			})
+			authorized.GET("/companies/autocomplete", func(context *gin.Context) {
+				companyHandlers.AutocompleteCompanies(context)
+			})
+			authorized.GET("/companies/:id", func(context *gin.Context) {
+				companyHandlers.GetCompany(context)
+			})
+			authorized.POST("/companies", func(context *gin.Context) {
+				companyHandlers.CreateCompany(context)
+			})
+			authorized.PUT("/companies/:id", func(context *gin.Context) {
+				companyHandlers.UpdateCompany(context)
+			})
+			authorized.DELETE("/companies/:id", func(context *gin.Context) {
+				companyHandlers.DeleteCompany(context)
+			})
+
+			authorized.GET("/catalog", func(context *gin.Context) {
+				catalogHandlers.ListCatalogItems(context)
+			})
+			authorized.GET("/catalog/usage", func(context *gin.Context) {
+				catalogHandlers.GetCatalogUsage(context)
+			})
+			authorized.GET("/catalog/:id", func(context *gin.Context) {
+				catalogHandlers.GetCatalogItem(context)
+			})
+			authorized.POST("/catalog", func(context *gin.Context) {
+				catalogHandlers.CreateCatalogItem(context)
+			})
+			authorized.PUT("/catalog/:id", func(context *gin.Context) {
+				catalogHandlers.UpdateCatalogItem(context)
+			})
+			authorized.DELETE("/catalog/:id", func(context *gin.Context) {
+				catalogHandlers.DeleteCatalogItem(context)
 			})
 
 			authorized.GET("/invoices", func(context *gin.Context) {
-				invoiceHandler.ListInvoices(context)
+				invoiceHandlers.ListInvoices(context)
+			})
+
+			authorized.GET("/invoices/:id", func(context *gin.Context) {
+				invoiceHandlers.GetInvoice(context)
 			})
 
 			authorized.POST("/invoices", func(context *gin.Context) {
-				invoiceHandler.CreateInvoice(context)
+				invoiceHandlers.CreateInvoice(context)
 			})
 
 			authorized.PUT("/invoices/:id", func(context *gin.Context) {
-				invoiceHandler.UpdateInvoice(context)
+				invoiceHandlers.UpdateInvoice(context)
 			})
 
 			authorized.DELETE("/invoices/:id", func(context *gin.Context) {
-				invoiceHandler.DeleteInvoice(context)
+				invoiceHandlers.DeleteInvoice(context)
+			})
+
+			// routes to move an invoice through its lifecycle
+			authorized.POST("/invoices/:id/issue", func(context *gin.Context) {
+				invoiceHandlers.IssueInvoice(context)
+			})
+			authorized.POST("/invoices/:id/send", func(context *gin.Context) {
+				invoiceHandlers.SendInvoice(context)
+			})
+			authorized.POST("/invoices/:id/cancel", func(context *gin.Context) {
+				invoiceHandlers.CancelInvoice(context)
+			})
+			authorized.GET("/invoices/:id/events", func(context *gin.Context) {
+				invoiceHandlers.ListInvoiceEvents(context)
+			})
+
+			// routes for credit and debit notes on issued invoices
+			authorized.POST("/invoices/:id/notes", func(context *gin.Context) {
+				noteHandlers.CreateNote(context)
+			})
+			authorized.GET("/invoices/:id/notes", func(context *gin.Context) {
+				noteHandlers.ListNotes(context)
+			})
+			authorized.GET("/notes/:id", func(context *gin.Context) {
+				noteHandlers.GetNote(context)
+			})
+			authorized.GET("/notes/:id/pdf", func(context *gin.Context) {
+				noteHandlers.GenerateNotePDF(context)
+			})
+
+			// routes for the payments ledger of an invoice
+			authorized.POST("/invoices/:id/payments", func(context *gin.Context) {
+				paymentHandlers.CreatePayment(context)
+			})
+			authorized.GET("/invoices/:id/payments", func(context *gin.Context) {
+				paymentHandlers.ListPayments(context)
+			})
+			authorized.POST("/invoices/:id/payments/:paymentID/reverse", func(context *gin.Context) {
+				paymentHandlers.ReversePayment(context)
+			})
+
+			// route for the accounts receivable aging report
+			authorized.GET("/reports/aging", func(context *gin.Context) {
+				reportHandlers.GetAgingReport(context)
+			})
+
+			// routes for the bulk PDF export, which runs in the background when it is large
+			authorized.POST("/invoices/export", func(context *gin.Context) {
+				invoiceHandlers.ExportInvoices(context)
+			})
+			authorized.GET("/exports/:id", func(context *gin.Context) {
+				invoiceHandlers.GetExport(context)
+			})
+			authorized.GET("/exports/:id/download", func(context *gin.Context) {
+				invoiceHandlers.DownloadExport(context)
 			})
 
 			// route to generate PDFs
 			authorized.GET("/invoices/:id/pdf", func(context *gin.Context) {
-				invoiceHandler.GeneratePDF(context)
+				invoiceHandlers.GeneratePDF(context)
+			})
+
+			// route to export the DIAN UBL 2.1 XML
+			authorized.GET("/invoices/:id/xml", func(context *gin.Context) {
+				invoiceHandlers.GetInvoiceXML(context)
 			})
 
 			// route to handle logout requests
 			authorized.POST("/logout", func(context *gin.Context) {
-				authHandler.Logout(context)
+				authHandlers.Logout(context)
 			})
 		}
 	}
@@ -0,0 +1,158 @@
+package taxes
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"fmt"
+	"strings"
+)
+
+// Apply calcula el IVA de cada servicio, las retenciones solicitadas y los
+// totales de la factura. Los subtotales de los servicios ya deben estar
+// calculados. Reemplaza Taxes, Subtotal, TotalIVA, TotalValue,
+// TotalWithholdings y NetPayable.
+func Apply(invoice *models.Invoice, rates Rates) error {
+	invoice.Taxes = nil
+	invoice.Subtotal = 0
+	invoice.TotalIVA = 0
+
+	// IVA por servicio, agrupado por tarifa en el resumen
+	ivaLines := map[string]*models.TaxLine{}
+	var ivaOrder []string
+	for i := range invoice.Services {
+		service := &invoice.Services[i]
+		if service.IVARate == "" {
+			service.IVARate = IVAExempt
+		}
+		service.IVARate = strings.ToLower(strings.TrimSpace(service.IVARate))
+		rate, ok := rates.IVA[service.IVARate]
+		if !ok {
+			return models.ErrorResponseInit(common.ErrInvalidTax, fmt.Sprintf("La tarifa de IVA '%s' del servicio %d no es válida. Usa 19, 5 o exento.", service.IVARate, i+1))
+		}
+
+		iva, err := service.Subtotal.Percent(rate)
+		if err != nil {
+			return models.AmountOutOfRange(fmt.Sprintf("servicios[%d].subtotal", i))
+		}
+		service.IVAAmount = iva.Round(models.DefaultCurrency)
+		if invoice.Subtotal, err = invoice.Subtotal.Add(service.Subtotal); err != nil {
+			return models.AmountOutOfRange("subtotal")
+		}
+		if invoice.TotalIVA, err = invoice.TotalIVA.Add(service.IVAAmount); err != nil {
+			return models.AmountOutOfRange("total_iva")
+		}
+
+		line, ok := ivaLines[service.IVARate]
+		if !ok {
+			line = &models.TaxLine{Type: TypeIVA, Description: ivaDescription(service.IVARate, rate), Rate: rate}
+			ivaLines[service.IVARate] = line
+			ivaOrder = append(ivaOrder, service.IVARate)
+		}
+		// Los subtotales no son negativos, así que cada tarifa suma menos que el total ya revisado
+		line.Base += service.Subtotal
+		line.Amount += service.IVAAmount
+	}
+	for _, category := range ivaOrder {
+		invoice.Taxes = append(invoice.Taxes, *ivaLines[category])
+	}
+	totalValue, err := invoice.Subtotal.Add(invoice.TotalIVA)
+	if err != nil {
+		return models.AmountOutOfRange("valor_total")
+	}
+	invoice.TotalValue = totalValue
+
+	// Retenciones a nivel de factura
+	invoice.TotalWithholdings = 0
+	seen := map[string]bool{}
+	for i := range invoice.Withholdings {
+		withholding := &invoice.Withholdings[i]
+		withholding.Type = strings.ToLower(strings.TrimSpace(withholding.Type))
+		if seen[withholding.Type] {
+			return models.ErrorResponseInit(common.ErrInvalidTax, fmt.Sprintf("La retención '%s' está repetida.", withholding.Type))
+		}
+		seen[withholding.Type] = true
+
+		line, err := withholdingLine(*withholding, invoice, rates)
+		if err == models.ErrMoneyOutOfRange {
+			return models.AmountOutOfRange(fmt.Sprintf("retenciones[%d]", i))
+		}
+		if err != nil {
+			return err
+		}
+		if line == nil {
+			continue
+		}
+		invoice.Taxes = append(invoice.Taxes, *line)
+		if invoice.TotalWithholdings, err = invoice.TotalWithholdings.Add(line.Amount); err != nil {
+			return models.AmountOutOfRange("total_retenciones")
+		}
+	}
+	invoice.NetPayable = invoice.TotalValue - invoice.TotalWithholdings
+
+	return nil
+}
+
+// withholdingLine calcula una retención. Devuelve nil si no aplica, por
+// ejemplo cuando la base no alcanza el mínimo en UVT o no hay IVA que retener,
+// y models.ErrMoneyOutOfRange si el valor no cabe en models.Money.
+func withholdingLine(withholding models.Withholding, invoice *models.Invoice, rates Rates) (*models.TaxLine, error) {
+	switch withholding.Type {
+	case TypeReteFuente:
+		withholding.Concept = strings.ToLower(strings.TrimSpace(withholding.Concept))
+		concept, ok := rates.ReteFuente[withholding.Concept]
+		if !ok {
+			return nil, models.ErrorResponseInit(common.ErrInvalidTax, fmt.Sprintf("El concepto de retención en la fuente '%s' no es válido.", withholding.Concept))
+		}
+		rate := rateOrOverride(concept.Rate, withholding.Rate)
+		minBase, err := rates.UVT.Multiply(concept.MinBaseUVT)
+		if err != nil {
+			return nil, err
+		}
+		if invoice.Subtotal < minBase {
+			return nil, nil
+		}
+		return taxLine(TypeReteFuente, "Retención en la fuente - "+concept.Description, invoice.Subtotal, rate)
+
+	case TypeReteIVA:
+		if invoice.TotalIVA == 0 {
+			return nil, nil
+		}
+		rate := rateOrOverride(rates.ReteIVA, withholding.Rate)
+		return taxLine(TypeReteIVA, "ReteIVA", invoice.TotalIVA, rate)
+
+	case TypeReteICA:
+		municipality := NormalizeMunicipality(withholding.Municipality)
+		configured, ok := rates.ReteICA[municipality]
+		if !ok && withholding.Rate == nil {
+			return nil, models.ErrorResponseInit(common.ErrInvalidTax, fmt.Sprintf("No hay tarifa de ReteICA configurada para el municipio '%s'; indica la tarifa.", withholding.Municipality))
+		}
+		rate := rateOrOverride(configured, withholding.Rate)
+		return taxLine(TypeReteICA, "ReteICA - "+strings.TrimSpace(withholding.Municipality), invoice.Subtotal, rate)
+
+	default:
+		return nil, models.ErrorResponseInit(common.ErrInvalidTax, fmt.Sprintf("El tipo de retención '%s' no es válido. Usa retefuente, reteiva o reteica.", withholding.Type))
+	}
+}
+
+// taxLine arma una línea de retención con el valor de aplicar rate a base.
+func taxLine(kind, description string, base models.Money, rate float64) (*models.TaxLine, error) {
+	amount, err := base.Percent(rate)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TaxLine{Type: kind, Description: description, Base: base, Rate: rate, Amount: amount.Round(models.DefaultCurrency)}, nil
+}
+
+func rateOrOverride(configured float64, override *float64) float64 {
+	if override != nil {
+		return *override
+	}
+	return configured
+}
+
+func ivaDescription(category string, rate float64) string {
+	if category == IVAExempt {
+		return "IVA exento"
+	}
+	return fmt.Sprintf("IVA %g%%", rate)
+}
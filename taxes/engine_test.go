@@ -0,0 +1,131 @@
+package taxes
+
+import (
+	"facturaexpress/common"
+	"facturaexpress/models"
+	"testing"
+)
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
+
+// invoiceWith arma una factura con las retenciones y los servicios dados.
+func invoiceWith(withholdings []models.Withholding, services ...models.Service) *models.Invoice {
+	return &models.Invoice{Services: services, Withholdings: withholdings}
+}
+
+func TestApplyIVA(t *testing.T) {
+	invoice := invoiceWith(nil,
+		models.Service{Subtotal: 100000000, IVARate: "19"},
+		models.Service{Subtotal: 20000000, IVARate: " 5 "},
+		models.Service{Subtotal: 5000000},
+		models.Service{Subtotal: 50000000, IVARate: "19"},
+	)
+	if err := Apply(invoice, DefaultRates()); err != nil {
+		t.Fatal(err)
+	}
+
+	if invoice.Subtotal != 175000000 || invoice.TotalIVA != 29500000 || invoice.TotalValue != 204500000 || invoice.NetPayable != 204500000 {
+		t.Fatalf("totales: subtotal %s, IVA %s, total %s, neto %s", invoice.Subtotal, invoice.TotalIVA, invoice.TotalValue, invoice.NetPayable)
+	}
+	if invoice.Services[1].IVARate != "5" || invoice.Services[2].IVARate != IVAExempt {
+		t.Errorf("tarifas normalizadas: %q y %q", invoice.Services[1].IVARate, invoice.Services[2].IVARate)
+	}
+	// Una línea de IVA por tarifa, en el orden en que aparecen
+	want := []models.TaxLine{
+		{Type: TypeIVA, Description: "IVA 19%", Base: 150000000, Rate: 19, Amount: 28500000},
+		{Type: TypeIVA, Description: "IVA 5%", Base: 20000000, Rate: 5, Amount: 1000000},
+		{Type: TypeIVA, Description: "IVA exento", Base: 5000000, Rate: 0, Amount: 0},
+	}
+	if len(invoice.Taxes) != len(want) {
+		t.Fatalf("impuestos: %+v", invoice.Taxes)
+	}
+	for i := range want {
+		if invoice.Taxes[i] != want[i] {
+			t.Errorf("impuestos[%d] = %+v, se esperaba %+v", i, invoice.Taxes[i], want[i])
+		}
+	}
+}
+
+func TestApplyWithholdings(t *testing.T) {
+	rates := DefaultRates()
+	invoice := invoiceWith([]models.Withholding{
+		{Type: "ReteFuente", Concept: "Servicios"},
+		{Type: TypeReteIVA},
+		{Type: TypeReteICA, Municipality: "Bogotá"},
+	}, models.Service{Subtotal: 100000000, IVARate: "19"})
+	if err := Apply(invoice, rates); err != nil {
+		t.Fatal(err)
+	}
+
+	// retefuente 4 % de 1.000.000, reteIVA 15 % de 190.000 y reteICA 0,966 % de 1.000.000
+	wantAmounts := map[string]models.Money{TypeReteFuente: 4000000, TypeReteIVA: 2850000, TypeReteICA: 966000}
+	for _, line := range invoice.Taxes[1:] {
+		if want, ok := wantAmounts[line.Type]; !ok || line.Amount != want {
+			t.Errorf("%s = %s, se esperaba %s", line.Type, line.Amount, want)
+		}
+		delete(wantAmounts, line.Type)
+	}
+	if len(wantAmounts) > 0 {
+		t.Errorf("faltan retenciones: %v", wantAmounts)
+	}
+	if invoice.TotalWithholdings != 7816000 || invoice.NetPayable != 119000000-7816000 {
+		t.Errorf("retenciones %s, neto %s", invoice.TotalWithholdings, invoice.NetPayable)
+	}
+}
+
+func TestApplyWithholdingRules(t *testing.T) {
+	rates := DefaultRates()
+
+	// La retención en la fuente de servicios exige una base de 4 UVT
+	minBase, err := rates.UVT.Multiply(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	small := invoiceWith([]models.Withholding{{Type: TypeReteFuente, Concept: "servicios"}}, models.Service{Subtotal: minBase - 1})
+	if err := Apply(small, rates); err != nil {
+		t.Fatal(err)
+	}
+	if small.TotalWithholdings != 0 {
+		t.Errorf("se practicó retefuente por debajo de la base mínima: %s", small.TotalWithholdings)
+	}
+
+	// Sin IVA no hay ReteIVA
+	exempt := invoiceWith([]models.Withholding{{Type: TypeReteIVA}}, models.Service{Subtotal: 100000000})
+	if err := Apply(exempt, rates); err != nil {
+		t.Fatal(err)
+	}
+	if exempt.TotalWithholdings != 0 {
+		t.Errorf("se practicó ReteIVA sin IVA: %s", exempt.TotalWithholdings)
+	}
+
+	// La tarifa enviada reemplaza la configurada, también en municipios sin tarifa
+	override := invoiceWith([]models.Withholding{{Type: TypeReteICA, Municipality: "Tunja", Rate: floatPtr(1.2)}}, models.Service{Subtotal: 100000000})
+	if err := Apply(override, rates); err != nil {
+		t.Fatal(err)
+	}
+	if override.TotalWithholdings != 1200000 {
+		t.Errorf("ReteICA con tarifa enviada = %s, se esperaba 12000.00", override.TotalWithholdings)
+	}
+}
+
+func TestApplyRejectsInvalidTaxes(t *testing.T) {
+	tests := []struct {
+		name    string
+		invoice *models.Invoice
+	}{
+		{"tarifa de IVA", invoiceWith(nil, models.Service{Subtotal: 100, IVARate: "16"})},
+		{"retención repetida", invoiceWith([]models.Withholding{{Type: TypeReteIVA}, {Type: "reteiva"}}, models.Service{Subtotal: 100})},
+		{"concepto", invoiceWith([]models.Withholding{{Type: TypeReteFuente, Concept: "otro"}}, models.Service{Subtotal: 100})},
+		{"municipio sin tarifa", invoiceWith([]models.Withholding{{Type: TypeReteICA, Municipality: "Tunja"}}, models.Service{Subtotal: 100})},
+		{"tipo de retención", invoiceWith([]models.Withholding{{Type: "reteotro"}}, models.Service{Subtotal: 100})},
+	}
+	for _, tt := range tests {
+		err := Apply(tt.invoice, DefaultRates())
+		errorJson, ok := err.(*models.ErrorJson)
+		if !ok || errorJson.Title != common.ErrInvalidTax {
+			t.Errorf("%s: se esperaba INVALID_TAX, se obtuvo %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package taxes
+
+import (
+	"encoding/json"
+	"facturaexpress/models"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Tipos de impuesto y retención que maneja el motor.
+const (
+	TypeIVA        = "iva"
+	TypeReteFuente = "retefuente"
+	TypeReteIVA    = "reteiva"
+	TypeReteICA    = "reteica"
+)
+
+// IVAExempt es la categoría de IVA que se aplica cuando un servicio no indica ninguna.
+const IVAExempt = "exento"
+
+// ReteFuenteConcept es un concepto de retención en la fuente. La retención solo
+// se practica si la base alcanza MinBaseUVT unidades de valor tributario.
+type ReteFuenteConcept struct {
+	Description string  `json:"descripcion"`
+	Rate        float64 `json:"tarifa"`
+	MinBaseUVT  float64 `json:"base_minima_uvt"`
+}
+
+// Rates contiene las tarifas vigentes. Todas las tarifas están en porcentaje;
+// las de ReteICA, que suelen publicarse por mil, también (9,66 ‰ = 0.966).
+type Rates struct {
+	UVT        models.Money                 `json:"uvt"`
+	IVA        map[string]float64           `json:"iva"`
+	ReteFuente map[string]ReteFuenteConcept `json:"retefuente"`
+	ReteIVA    float64                      `json:"reteiva"`
+	ReteICA    map[string]float64           `json:"reteica"`
+}
+
+// DefaultRates devuelve las tarifas generales. Cambian con la normativa y con
+// cada municipio, por eso se pueden reemplazar con LoadRates.
+func DefaultRates() Rates {
+	return Rates{
+		// UVT 2025; actualizar cada año con la resolución de la DIAN
+		UVT: 4979900,
+		IVA: map[string]float64{
+			"19":      19,
+			"5":       5,
+			IVAExempt: 0,
+		},
+		ReteFuente: map[string]ReteFuenteConcept{
+			"servicios":                 {Description: "Servicios generales (declarantes)", Rate: 4, MinBaseUVT: 4},
+			"servicios_no_declarantes":  {Description: "Servicios generales (no declarantes)", Rate: 6, MinBaseUVT: 4},
+			"honorarios":                {Description: "Honorarios y comisiones (personas jurídicas)", Rate: 11},
+			"honorarios_no_declarantes": {Description: "Honorarios y comisiones (no declarantes)", Rate: 10},
+			"compras":                   {Description: "Compras generales (declarantes)", Rate: 2.5, MinBaseUVT: 27},
+			"arrendamiento_inmuebles":   {Description: "Arrendamiento de bienes inmuebles", Rate: 3.5, MinBaseUVT: 27},
+			"transporte_carga":          {Description: "Transporte de carga", Rate: 1, MinBaseUVT: 4},
+		},
+		ReteIVA: 15,
+		ReteICA: map[string]float64{
+			"bogota":       0.966,
+			"barranquilla": 0.8,
+			"cartagena":    0.8,
+			"medellin":     1,
+			"cali":         1,
+		},
+	}
+}
+
+// LoadRates lee un archivo JSON con la misma forma que Rates y lo combina con
+// las tarifas predeterminadas: lo que el archivo define reemplaza al valor por defecto.
+func LoadRates(path string) (Rates, error) {
+	rates := DefaultRates()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return rates, fmt.Errorf("error al leer el archivo de tarifas: %v", err)
+	}
+
+	var custom Rates
+	if err := json.Unmarshal(content, &custom); err != nil {
+		return rates, fmt.Errorf("error al interpretar el archivo de tarifas: %v", err)
+	}
+
+	if custom.UVT != 0 {
+		rates.UVT = custom.UVT
+	}
+	if custom.ReteIVA != 0 {
+		rates.ReteIVA = custom.ReteIVA
+	}
+	for category, rate := range custom.IVA {
+		rates.IVA[category] = rate
+	}
+	for code, concept := range custom.ReteFuente {
+		rates.ReteFuente[code] = concept
+	}
+	for municipality, rate := range custom.ReteICA {
+		rates.ReteICA[NormalizeMunicipality(municipality)] = rate
+	}
+	return rates, nil
+}
+
+// NormalizeMunicipality pasa el nombre del municipio a minúsculas y sin tildes,
+// que es como se usa como clave de ReteICA ("Bogotá" -> "bogota").
+func NormalizeMunicipality(name string) string {
+	replacer := strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+	return replacer.Replace(strings.ToLower(strings.TrimSpace(name)))
+}
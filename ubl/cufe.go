@@ -0,0 +1,87 @@
+package ubl
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"facturaexpress/models"
+	"fmt"
+	"strings"
+)
+
+// Códigos de los tributos que entran en el CUFE: IVA, impuesto al consumo e ICA.
+const (
+	cufeTaxIVA = "01"
+	cufeTaxINC = "04"
+	cufeTaxICA = "03"
+)
+
+// URLs de consulta de documentos de la DIAN por ambiente.
+const (
+	verificationURLProduction = "https://catalogo-vpfe.dian.gov.co/document/searchqr?documentkey="
+	verificationURLTesting    = "https://catalogo-vpfe-hab.dian.gov.co/document/searchqr?documentkey="
+)
+
+// CUFE calcula el código único de facturación electrónica: el SHA-384, en
+// hexadecimal, de la concatenación de número, fecha, hora, valor antes de
+// impuestos, cada tributo con su valor, valor total, NIT del emisor,
+// documento del adquiriente, clave técnica y ambiente. La factura ya debe
+// tener número.
+func CUFE(invoice models.Invoice, settings Settings) (string, error) {
+	if invoice.Number == "" {
+		return "", fmt.Errorf("la factura no tiene número")
+	}
+	if settings.TechnicalKey == "" {
+		return "", fmt.Errorf("falta la clave técnica (DIAN_TECHNICAL_KEY)")
+	}
+	issueDate, issueTime, err := issueDateTime(invoice.Date)
+	if err != nil {
+		return "", err
+	}
+
+	var zero models.Money
+	source := strings.Join([]string{
+		invoice.Number,
+		issueDate,
+		issueTime,
+		invoice.Subtotal.String(),
+		cufeTaxIVA, invoice.TotalIVA.String(),
+		cufeTaxINC, zero.String(),
+		cufeTaxICA, zero.String(),
+		invoice.TotalValue.String(),
+		supplierParty(invoice).Party.TaxScheme.CompanyID.Value,
+		customerParty(invoice).Party.TaxScheme.CompanyID.Value,
+		settings.TechnicalKey,
+		settings.Environment,
+	}, "")
+
+	sum := sha512.Sum384([]byte(source))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// VerificationURL es la dirección de consulta del documento en la DIAN.
+func VerificationURL(cufe string, settings Settings) string {
+	if settings.Environment == "1" {
+		return verificationURLProduction + cufe
+	}
+	return verificationURLTesting + cufe
+}
+
+// QRCodeContent es el texto del código QR de la representación gráfica, con
+// los datos que pide la DIAN y la URL de consulta al final.
+func QRCodeContent(invoice models.Invoice, settings Settings) string {
+	issueDate, issueTime, _ := issueDateTime(invoice.Date)
+	lines := []string{
+		"NumFac: " + invoice.Number,
+		"FecFac: " + issueDate,
+		"HorFac: " + issueTime,
+		"NitFac: " + supplierParty(invoice).Party.TaxScheme.CompanyID.Value,
+		"DocAdq: " + customerParty(invoice).Party.TaxScheme.CompanyID.Value,
+		"ValFac: " + invoice.Subtotal.String(),
+		"ValIva: " + invoice.TotalIVA.String(),
+		"ValOtroIm: 0.00",
+		"ValTolFac: " + invoice.TotalValue.String(),
+		"CUFE: " + invoice.CUFE,
+		"QRCode: " + VerificationURL(invoice.CUFE, settings),
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package ubl
+
+import (
+	"facturaexpress/models"
+	"testing"
+)
+
+// dianExampleInvoice es el ejemplo de cálculo del CUFE del anexo técnico de
+// la factura electrónica de la DIAN, con la fecha como la devuelve lib/pq.
+func dianExampleInvoice() models.Invoice {
+	return models.Invoice{
+		Number:     "323200000129",
+		Date:       "2019-01-16T10:53:10Z",
+		Operator:   models.Operator{DocumentType: "NIT", Document: "700085371"},
+		Company:    models.Company{TIN: "800199436"},
+		Subtotal:   150000000,
+		TotalIVA:   28500000,
+		TotalValue: 178500000,
+	}
+}
+
+var dianExampleSettings = Settings{Environment: "1", TechnicalKey: "693ff6f2a553c3646a063436fd4dd9ded0311471"}
+
+func TestCUFEMatchesDIANExample(t *testing.T) {
+	// NumFac + FecFac + HorFac + ValFac + 01 + ValImp1 + 04 + ValImp2 + 03 +
+	// ValImp3 + ValTot + NitOFE + NumAdq + ClTec + TipoAmbiente:
+	// 3232000001292019-01-1610:53:10-05:001500000.0001285000.00040.00030.001785000.00700085371800199436693ff6f2a553c3646a063436fd4dd9ded03114711
+	const want = "8bb918b19ba22a694f1da11c643b5e9de39adf60311cf179179e9b33381030bcd4c3c3f156c506ed5908f9276f5bd9b4"
+
+	got, err := CUFE(dianExampleInvoice(), dianExampleSettings)
+	if err != nil {
+		t.Fatalf("CUFE: %v", err)
+	}
+	if got != want {
+		t.Fatalf("CUFE = %s, se esperaba %s", got, want)
+	}
+}
+
+func TestCUFEIgnoresStoredZoneMarker(t *testing.T) {
+	utc := dianExampleInvoice()
+	bogota := dianExampleInvoice()
+	bogota.Date = "2019-01-16T10:53:10-05:00"
+
+	fromUTC, err := CUFE(utc, dianExampleSettings)
+	if err != nil {
+		t.Fatalf("CUFE: %v", err)
+	}
+	fromBogota, err := CUFE(bogota, dianExampleSettings)
+	if err != nil {
+		t.Fatalf("CUFE: %v", err)
+	}
+	if fromUTC != fromBogota {
+		t.Fatalf("el CUFE cambia con la zona con que llega la fecha: %s y %s", fromUTC, fromBogota)
+	}
+}
+
+func TestCUFERequiresNumberAndTechnicalKey(t *testing.T) {
+	invoice := dianExampleInvoice()
+	invoice.Number = ""
+	if _, err := CUFE(invoice, dianExampleSettings); err == nil {
+		t.Error("CUFE aceptó una factura sin número")
+	}
+	if _, err := CUFE(dianExampleInvoice(), Settings{Environment: "1"}); err == nil {
+		t.Error("CUFE aceptó una configuración sin clave técnica")
+	}
+}
+
+func TestQRCodeContentUsesColombianDate(t *testing.T) {
+	invoice := dianExampleInvoice()
+	invoice.Date = "2024-01-15T00:00:00Z"
+	content := QRCodeContent(invoice, dianExampleSettings)
+	for _, line := range []string{"FecFac: 2024-01-15", "HorFac: 00:00:00-05:00"} {
+		if !containsLine(content, line) {
+			t.Errorf("el QR no contiene %q:\n%s", line, content)
+		}
+	}
+}
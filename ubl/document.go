@@ -0,0 +1,214 @@
+package ubl
+
+import "encoding/xml"
+
+// Espacios de nombres de UBL 2.1 y de las extensiones de la DIAN. Los
+// elementos se declaran con el prefijo en la etiqueta (cbc:, cac:, ...) y el
+// documento raíz declara los prefijos, que es como la DIAN espera recibirlos.
+const (
+	NamespaceInvoice    = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	NamespaceAggregate  = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	NamespaceBasic      = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+	NamespaceExtensions = "urn:oasis:names:specification:ubl:schema:xsd:CommonExtensionComponents-2"
+	NamespaceDIAN       = "dian:gov:co:facturaelectronica:Structures-2-1"
+	NamespaceSignature  = "http://www.w3.org/2000/09/xmldsig#"
+)
+
+// Invoice es el documento Invoice de UBL 2.1 con las extensiones de la DIAN.
+// El orden de los campos es el que exige el esquema.
+type Invoice struct {
+	XMLName      xml.Name   `xml:"Invoice"`
+	Xmlns        string     `xml:"xmlns,attr"`
+	XmlnsCac     string     `xml:"xmlns:cac,attr"`
+	XmlnsCbc     string     `xml:"xmlns:cbc,attr"`
+	XmlnsExt     string     `xml:"xmlns:ext,attr"`
+	XmlnsSts     string     `xml:"xmlns:sts,attr"`
+	XmlnsDs      string     `xml:"xmlns:ds,attr"`
+	Extensions   Extensions `xml:"ext:UBLExtensions"`
+	UBLVersionID string     `xml:"cbc:UBLVersionID"`
+	// CustomizationID es el tipo de operación: 10 = estándar
+	CustomizationID    string             `xml:"cbc:CustomizationID"`
+	ProfileID          string             `xml:"cbc:ProfileID"`
+	ProfileExecutionID string             `xml:"cbc:ProfileExecutionID"`
+	ID                 string             `xml:"cbc:ID"`
+	UUID               *Identifier        `xml:"cbc:UUID,omitempty"`
+	IssueDate          string             `xml:"cbc:IssueDate"`
+	IssueTime          string             `xml:"cbc:IssueTime"`
+	InvoiceTypeCode    string             `xml:"cbc:InvoiceTypeCode"`
+	Notes              []string           `xml:"cbc:Note,omitempty"`
+	DocumentCurrency   string             `xml:"cbc:DocumentCurrencyCode"`
+	LineCount          int                `xml:"cbc:LineCountNumeric"`
+	Supplier           PartyRole          `xml:"cac:AccountingSupplierParty"`
+	Customer           PartyRole          `xml:"cac:AccountingCustomerParty"`
+	PaymentMeans       *PaymentMeans      `xml:"cac:PaymentMeans,omitempty"`
+	TaxTotals          []TaxTotal         `xml:"cac:TaxTotal"`
+	WithholdingTotals  []TaxTotal         `xml:"cac:WithholdingTaxTotal"`
+	LegalMonetaryTotal LegalMonetaryTotal `xml:"cac:LegalMonetaryTotal"`
+	Lines              []InvoiceLine      `xml:"cac:InvoiceLine"`
+}
+
+type Extensions struct {
+	Extension []Extension `xml:"ext:UBLExtension"`
+}
+
+type Extension struct {
+	Content ExtensionContent `xml:"ext:ExtensionContent"`
+}
+
+type ExtensionContent struct {
+	DianExtensions *DianExtensions `xml:"sts:DianExtensions,omitempty"`
+}
+
+// DianExtensions lleva los datos de la resolución de numeración y del origen de la factura.
+type DianExtensions struct {
+	InvoiceControl *InvoiceControl `xml:"sts:InvoiceControl,omitempty"`
+	InvoiceSource  InvoiceSource   `xml:"sts:InvoiceSource"`
+	QRCode         string          `xml:"sts:QRCode,omitempty"`
+}
+
+type InvoiceControl struct {
+	InvoiceAuthorization string             `xml:"sts:InvoiceAuthorization"`
+	AuthorizationPeriod  Period             `xml:"sts:AuthorizationPeriod"`
+	AuthorizedInvoices   AuthorizedInvoices `xml:"sts:AuthorizedInvoices"`
+}
+
+type Period struct {
+	StartDate string `xml:"cbc:StartDate"`
+	EndDate   string `xml:"cbc:EndDate"`
+}
+
+type AuthorizedInvoices struct {
+	Prefix string `xml:"sts:Prefix,omitempty"`
+	From   int64  `xml:"sts:From"`
+	To     int64  `xml:"sts:To"`
+}
+
+type InvoiceSource struct {
+	IdentificationCode Code `xml:"cbc:IdentificationCode"`
+}
+
+// Code es un código de una lista de la DIAN o de UN/ECE.
+type Code struct {
+	ListAgencyID   string `xml:"listAgencyID,attr,omitempty"`
+	ListAgencyName string `xml:"listAgencyName,attr,omitempty"`
+	ListSchemeURI  string `xml:"listSchemeURI,attr,omitempty"`
+	Value          string `xml:",chardata"`
+}
+
+// Identifier es un identificador con su esquema, p. ej. un NIT con su dígito
+// de verificación o el CUFE.
+type Identifier struct {
+	SchemeAgencyID   string `xml:"schemeAgencyID,attr,omitempty"`
+	SchemeAgencyName string `xml:"schemeAgencyName,attr,omitempty"`
+	SchemeID         string `xml:"schemeID,attr,omitempty"`
+	SchemeName       string `xml:"schemeName,attr,omitempty"`
+	Value            string `xml:",chardata"`
+}
+
+type PartyRole struct {
+	// AdditionalAccountID es el tipo de persona: 1 = jurídica, 2 = natural
+	AdditionalAccountID string `xml:"cbc:AdditionalAccountID"`
+	Party               Party  `xml:"cac:Party"`
+}
+
+type Party struct {
+	Name        PartyName        `xml:"cac:PartyName"`
+	TaxScheme   PartyTaxScheme   `xml:"cac:PartyTaxScheme"`
+	LegalEntity PartyLegalEntity `xml:"cac:PartyLegalEntity"`
+	Contact     *Contact         `xml:"cac:Contact,omitempty"`
+}
+
+type PartyName struct {
+	Name string `xml:"cbc:Name"`
+}
+
+type PartyTaxScheme struct {
+	RegistrationName string     `xml:"cbc:RegistrationName"`
+	CompanyID        Identifier `xml:"cbc:CompanyID"`
+	TaxLevelCode     string     `xml:"cbc:TaxLevelCode"`
+	TaxScheme        TaxScheme  `xml:"cac:TaxScheme"`
+}
+
+type PartyLegalEntity struct {
+	RegistrationName string     `xml:"cbc:RegistrationName"`
+	CompanyID        Identifier `xml:"cbc:CompanyID"`
+}
+
+type Contact struct {
+	Telephone string `xml:"cbc:Telephone"`
+}
+
+type TaxScheme struct {
+	ID   string `xml:"cbc:ID"`
+	Name string `xml:"cbc:Name"`
+}
+
+type PaymentMeans struct {
+	// ID es la forma de pago (1 = contado) y Code el medio de pago (42 = consignación bancaria)
+	ID        string `xml:"cbc:ID"`
+	Code      string `xml:"cbc:PaymentMeansCode"`
+	PaymentID string `xml:"cbc:PaymentID,omitempty"`
+}
+
+// Amount es un valor monetario con su moneda.
+type Amount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+type TaxTotal struct {
+	TaxAmount    Amount        `xml:"cbc:TaxAmount"`
+	TaxSubtotals []TaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type TaxSubtotal struct {
+	TaxableAmount Amount      `xml:"cbc:TaxableAmount"`
+	TaxAmount     Amount      `xml:"cbc:TaxAmount"`
+	TaxCategory   TaxCategory `xml:"cac:TaxCategory"`
+}
+
+type TaxCategory struct {
+	Percent   string    `xml:"cbc:Percent"`
+	TaxScheme TaxScheme `xml:"cac:TaxScheme"`
+}
+
+type LegalMonetaryTotal struct {
+	LineExtensionAmount Amount `xml:"cbc:LineExtensionAmount"`
+	TaxExclusiveAmount  Amount `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusiveAmount  Amount `xml:"cbc:TaxInclusiveAmount"`
+	PayableAmount       Amount `xml:"cbc:PayableAmount"`
+}
+
+type InvoiceLine struct {
+	ID                  int               `xml:"cbc:ID"`
+	InvoicedQuantity    Quantity          `xml:"cbc:InvoicedQuantity"`
+	LineExtensionAmount Amount            `xml:"cbc:LineExtensionAmount"`
+	AllowanceCharges    []AllowanceCharge `xml:"cac:AllowanceCharge"`
+	TaxTotals           []TaxTotal        `xml:"cac:TaxTotal"`
+	Item                Item              `xml:"cac:Item"`
+	Price               Price             `xml:"cac:Price"`
+}
+
+type Quantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// AllowanceCharge es un descuento (ChargeIndicator false) o un cargo sobre la línea.
+type AllowanceCharge struct {
+	ID                      int    `xml:"cbc:ID"`
+	ChargeIndicator         bool   `xml:"cbc:ChargeIndicator"`
+	AllowanceChargeReason   string `xml:"cbc:AllowanceChargeReason,omitempty"`
+	MultiplierFactorNumeric string `xml:"cbc:MultiplierFactorNumeric"`
+	Amount                  Amount `xml:"cbc:Amount"`
+	BaseAmount              Amount `xml:"cbc:BaseAmount"`
+}
+
+type Item struct {
+	Description string `xml:"cbc:Description"`
+}
+
+type Price struct {
+	PriceAmount  Amount   `xml:"cbc:PriceAmount"`
+	BaseQuantity Quantity `xml:"cbc:BaseQuantity"`
+}
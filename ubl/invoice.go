@@ -0,0 +1,333 @@
+package ubl
+
+import (
+	"encoding/xml"
+	"facturaexpress/models"
+	"facturaexpress/taxes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Settings son los datos de la facturación electrónica que no dependen de cada factura.
+type Settings struct {
+	// Environment es el ambiente de la DIAN: "1" producción, "2" pruebas
+	Environment string
+	// TechnicalKey es la clave técnica de la resolución, necesaria para el CUFE
+	TechnicalKey string
+}
+
+// SettingsFromEnv lee DIAN_ENVIRONMENT y DIAN_TECHNICAL_KEY; si el ambiente
+// no está definido usa el de pruebas.
+func SettingsFromEnv() Settings {
+	settings := Settings{Environment: os.Getenv("DIAN_ENVIRONMENT"), TechnicalKey: os.Getenv("DIAN_TECHNICAL_KEY")}
+	if settings.Environment != "1" {
+		settings.Environment = "2"
+	}
+	return settings
+}
+
+// dianAgency identifica a la DIAN como agencia de los esquemas de identificación.
+const (
+	dianAgencyID   = "195"
+	dianAgencyName = "CO, DIAN (Dirección de Impuestos y Aduanas Nacionales)"
+)
+
+// documentTypeCodes traduce los tipos de documento a la tabla 13.2.1 del anexo técnico.
+var documentTypeCodes = map[string]string{
+	"RC":  "11",
+	"TI":  "12",
+	"CC":  "13",
+	"TE":  "21",
+	"CE":  "22",
+	"NIT": "31",
+	"PAS": "41",
+	"PA":  "41",
+	"DIE": "42",
+	"PEP": "47",
+}
+
+// taxSchemes traduce los tipos de impuesto del motor de impuestos a los tributos de la DIAN.
+var taxSchemes = map[string]TaxScheme{
+	taxes.TypeIVA:        {ID: "01", Name: "IVA"},
+	taxes.TypeReteIVA:    {ID: "05", Name: "ReteIVA"},
+	taxes.TypeReteFuente: {ID: "06", Name: "ReteRenta"},
+	taxes.TypeReteICA:    {ID: "07", Name: "ReteICA"},
+}
+
+// unitCodes traduce las unidades más comunes a códigos UN/ECE; el resto se reporta como unidad (94).
+var unitCodes = map[string]string{
+	"hora":   "HUR",
+	"horas":  "HUR",
+	"dia":    "DAY",
+	"día":    "DAY",
+	"dias":   "DAY",
+	"días":   "DAY",
+	"mes":    "MON",
+	"meses":  "MON",
+	"kg":     "KGM",
+	"metro":  "MTR",
+	"metros": "MTR",
+}
+
+// NewInvoice arma el documento UBL de una factura. numbering es la numeración
+// del emisor y puede ser nil si aún no la ha configurado; en ese caso el
+// documento no lleva el control de la resolución.
+func NewInvoice(invoice models.Invoice, numbering *models.Numbering, settings Settings) (Invoice, error) {
+	issueDate, issueTime, err := issueDateTime(invoice.Date)
+	if err != nil {
+		return Invoice{}, err
+	}
+
+	doc := Invoice{
+		Xmlns:              NamespaceInvoice,
+		XmlnsCac:           NamespaceAggregate,
+		XmlnsCbc:           NamespaceBasic,
+		XmlnsExt:           NamespaceExtensions,
+		XmlnsSts:           NamespaceDIAN,
+		XmlnsDs:            NamespaceSignature,
+		UBLVersionID:       "UBL 2.1",
+		CustomizationID:    "10",
+		ProfileID:          "DIAN 2.1: Factura Electrónica de Venta",
+		ProfileExecutionID: settings.Environment,
+		ID:                 invoiceID(invoice),
+		IssueDate:          issueDate,
+		IssueTime:          issueTime,
+		InvoiceTypeCode:    "01",
+		DocumentCurrency:   models.DefaultCurrency.Code,
+		LineCount:          len(invoice.Services),
+		Supplier:           supplierParty(invoice),
+		Customer:           customerParty(invoice),
+		PaymentMeans:       paymentMeans(invoice.Operator),
+	}
+	if invoice.CUFE != "" {
+		doc.UUID = &Identifier{SchemeID: settings.Environment, SchemeName: "CUFE-SHA384", Value: invoice.CUFE}
+	}
+	if invoice.AmountInWords != "" {
+		doc.Notes = append(doc.Notes, invoice.AmountInWords)
+	}
+
+	dian := &DianExtensions{InvoiceSource: InvoiceSource{IdentificationCode: Code{
+		ListAgencyID:   "6",
+		ListAgencyName: "United Nations Economic Commission for Europe",
+		ListSchemeURI:  "urn:oasis:names:specification:ubl:codelist:gc:CountryIdentificationCode-2.1",
+		Value:          "CO",
+	}}}
+	if invoice.CUFE != "" {
+		dian.QRCode = QRCodeContent(invoice, settings)
+	}
+	if numbering != nil {
+		dian.InvoiceControl = &InvoiceControl{
+			InvoiceAuthorization: numbering.ResolutionNumber,
+			AuthorizationPeriod:  Period{StartDate: numbering.ValidFrom, EndDate: numbering.ValidTo},
+			AuthorizedInvoices:   AuthorizedInvoices{Prefix: numbering.Prefix, From: numbering.RangeFrom, To: numbering.RangeTo},
+		}
+	}
+	doc.Extensions = Extensions{Extension: []Extension{{Content: ExtensionContent{DianExtensions: dian}}}}
+
+	// Impuestos y retenciones agrupados por tributo
+	var taxableAmount models.Money
+	taxTotals := map[string]*TaxTotal{}
+	taxSums := map[string]models.Money{}
+	var order []string
+	for _, tax := range invoice.Taxes {
+		scheme, ok := taxSchemes[tax.Type]
+		if !ok {
+			continue
+		}
+		total, ok := taxTotals[tax.Type]
+		if !ok {
+			total = &TaxTotal{}
+			taxTotals[tax.Type] = total
+			order = append(order, tax.Type)
+		}
+		total.TaxSubtotals = append(total.TaxSubtotals, TaxSubtotal{
+			TaxableAmount: amount(tax.Base),
+			TaxAmount:     amount(tax.Amount),
+			TaxCategory:   TaxCategory{Percent: percent(tax.Rate), TaxScheme: scheme},
+		})
+		taxSums[tax.Type] += tax.Amount
+		if tax.Type == taxes.TypeIVA && tax.Rate > 0 {
+			taxableAmount += tax.Base
+		}
+	}
+	for _, taxType := range order {
+		total := taxTotals[taxType]
+		total.TaxAmount = amount(taxSums[taxType])
+		if taxType == taxes.TypeIVA {
+			doc.TaxTotals = append(doc.TaxTotals, *total)
+		} else {
+			doc.WithholdingTotals = append(doc.WithholdingTotals, *total)
+		}
+	}
+
+	// Las retenciones no reducen el valor a pagar del documento electrónico
+	doc.LegalMonetaryTotal = LegalMonetaryTotal{
+		LineExtensionAmount: amount(invoice.Subtotal),
+		TaxExclusiveAmount:  amount(taxableAmount),
+		TaxInclusiveAmount:  amount(invoice.TotalValue),
+		PayableAmount:       amount(invoice.TotalValue),
+	}
+
+	for i, service := range invoice.Services {
+		doc.Lines = append(doc.Lines, invoiceLine(i+1, service))
+	}
+
+	return doc, nil
+}
+
+// Marshal serializa el documento con la declaración XML.
+func Marshal(doc Invoice) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// issueDateTime separa la fecha de la factura en fecha y hora de Colombia,
+// como se reportan en el XML y en el CUFE.
+func issueDateTime(date string) (string, string, error) {
+	issuedAt, err := models.ParseInvoiceDate(date)
+	if err != nil {
+		return "", "", fmt.Errorf("fecha de la factura inválida: %v", err)
+	}
+	return issuedAt.Format("2006-01-02"), issuedAt.Format("15:04:05-07:00"), nil
+}
+
+func invoiceID(invoice models.Invoice) string {
+	if invoice.Number != "" {
+		return invoice.Number
+	}
+	return strconv.Itoa(invoice.ID)
+}
+
+func supplierParty(invoice models.Invoice) PartyRole {
+	operator := invoice.Operator
+	id := partyIdentifier(operator.DocumentType, operator.Document)
+	party := Party{
+		Name: PartyName{Name: operator.Name},
+		TaxScheme: PartyTaxScheme{
+			RegistrationName: operator.Name,
+			CompanyID:        id,
+			TaxLevelCode:     "R-99-PN",
+			TaxScheme:        partyTaxScheme(invoice.TotalIVA > 0),
+		},
+		LegalEntity: PartyLegalEntity{RegistrationName: operator.Name, CompanyID: id},
+	}
+	if operator.Cellphone != "" {
+		party.Contact = &Contact{Telephone: operator.Cellphone}
+	}
+	additionalAccountID := "2"
+	if id.SchemeName == documentTypeCodes["NIT"] {
+		additionalAccountID = "1"
+	}
+	return PartyRole{AdditionalAccountID: additionalAccountID, Party: party}
+}
+
+func customerParty(invoice models.Invoice) PartyRole {
+	company := invoice.Company
+	id := partyIdentifier("NIT", company.TIN)
+	return PartyRole{
+		AdditionalAccountID: "1",
+		Party: Party{
+			Name: PartyName{Name: company.Name},
+			TaxScheme: PartyTaxScheme{
+				RegistrationName: company.Name,
+				CompanyID:        id,
+				TaxLevelCode:     "R-99-PN",
+				TaxScheme:        partyTaxScheme(false),
+			},
+			LegalEntity: PartyLegalEntity{RegistrationName: company.Name, CompanyID: id},
+		},
+	}
+}
+
+// partyIdentifier arma el identificador de una persona. Para un NIT escrito
+// como "900123456-7" separa el dígito de verificación en schemeID.
+func partyIdentifier(documentType, document string) Identifier {
+	normalized := strings.ToUpper(strings.NewReplacer(".", "", " ", "").Replace(documentType))
+	code, ok := documentTypeCodes[normalized]
+	if !ok {
+		code = documentTypeCodes["CC"]
+	}
+
+	id := Identifier{SchemeAgencyID: dianAgencyID, SchemeAgencyName: dianAgencyName, SchemeName: code}
+	document = strings.NewReplacer(".", "", " ", "").Replace(document)
+	if code == documentTypeCodes["NIT"] {
+		if number, checkDigit, found := strings.Cut(document, "-"); found {
+			document = number
+			id.SchemeID = checkDigit
+		}
+	}
+	id.Value = document
+	return id
+}
+
+func partyTaxScheme(chargesIVA bool) TaxScheme {
+	if chargesIVA {
+		return taxSchemes[taxes.TypeIVA]
+	}
+	return TaxScheme{ID: "ZZ", Name: "No aplica"}
+}
+
+func paymentMeans(operator models.Operator) *PaymentMeans {
+	if operator.BankAccountNumber == "" {
+		return &PaymentMeans{ID: "1", Code: "10"}
+	}
+	return &PaymentMeans{ID: "1", Code: "42", PaymentID: operator.BankAccountNumber}
+}
+
+func invoiceLine(number int, service models.Service) InvoiceLine {
+	unitCode, ok := unitCodes[strings.ToLower(strings.TrimSpace(service.Unit))]
+	if !ok {
+		unitCode = "94"
+	}
+	quantity := strconv.FormatFloat(service.Quantity, 'f', -1, 64)
+
+	line := InvoiceLine{
+		ID:                  number,
+		InvoicedQuantity:    Quantity{UnitCode: unitCode, Value: quantity},
+		LineExtensionAmount: amount(service.Subtotal),
+		Item:                Item{Description: service.Description},
+		Price: Price{
+			PriceAmount:  amount(service.UnitPrice),
+			BaseQuantity: Quantity{UnitCode: unitCode, Value: "1"},
+		},
+	}
+
+	if service.Discount > 0 {
+		gross := service.Subtotal + service.Discount
+		line.AllowanceCharges = append(line.AllowanceCharges, AllowanceCharge{
+			ID:                      1,
+			ChargeIndicator:         false,
+			AllowanceChargeReason:   "Descuento",
+			MultiplierFactorNumeric: percent(float64(service.Discount) * 100 / float64(gross)),
+			Amount:                  amount(service.Discount),
+			BaseAmount:              amount(gross),
+		})
+	}
+
+	if service.IVARate != "" {
+		rate, _ := strconv.ParseFloat(service.IVARate, 64)
+		line.TaxTotals = append(line.TaxTotals, TaxTotal{
+			TaxAmount: amount(service.IVAAmount),
+			TaxSubtotals: []TaxSubtotal{{
+				TaxableAmount: amount(service.Subtotal),
+				TaxAmount:     amount(service.IVAAmount),
+				TaxCategory:   TaxCategory{Percent: percent(rate), TaxScheme: taxSchemes[taxes.TypeIVA]},
+			}},
+		})
+	}
+
+	return line
+}
+
+func amount(value models.Money) Amount {
+	return Amount{CurrencyID: models.DefaultCurrency.Code, Value: value.String()}
+}
+
+func percent(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', 2, 64)
+}
@@ -0,0 +1,322 @@
+package ubl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"facturaexpress/models"
+	"facturaexpress/taxes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// El XML generado se compara con un archivo de referencia (go test ./ubl
+// -update lo regenera) y el orden de los elementos se revisa contra las
+// secuencias de los esquemas de UBL 2.1. La validación contra los XSD, con
+// xmllint, está en xsd_test.go.
+var update = flag.Bool("update", false, "regenera los archivos de testdata")
+
+// sampleInvoice es una factura con IVA, retención, descuento y numeración.
+func sampleInvoice() (models.Invoice, *models.Numbering) {
+	invoice := models.Invoice{
+		ID:            7,
+		Number:        "FE-990000001",
+		Date:          "2024-01-15T00:00:00Z",
+		AmountInWords: "UN MILLÓN CIENTO NOVENTA MIL PESOS M/CTE",
+		Operator: models.Operator{
+			Name:              "Operador de Prueba",
+			DocumentType:      "C.C.",
+			Document:          "1.020.304.050",
+			Cellphone:         "3001234567",
+			BankAccountNumber: "123456789",
+		},
+		Company: models.Company{Name: "Empresa de Prueba S.A.S.", TIN: "900.123.456-8"},
+		Services: []models.Service{
+			{Description: "Desarrollo de software", Quantity: 10, Unit: "horas", UnitPrice: 10000000, Discount: 0, Subtotal: 100000000, IVARate: "19", IVAAmount: 19000000},
+			{Description: "Soporte", Quantity: 1, Unit: "mes", UnitPrice: 10000000, Discount: 10000000, Subtotal: 0, IVARate: "0"},
+		},
+		Taxes: []models.TaxLine{
+			{Type: taxes.TypeIVA, Description: "IVA 19%", Base: 100000000, Rate: 19, Amount: 19000000},
+			{Type: taxes.TypeReteFuente, Description: "Retención en la fuente", Base: 100000000, Rate: 11, Amount: 11000000},
+		},
+		Subtotal:   100000000,
+		TotalIVA:   19000000,
+		TotalValue: 119000000,
+		NetPayable: 108000000,
+	}
+	numbering := &models.Numbering{
+		Prefix:           "FE",
+		ResolutionNumber: "18760000001",
+		ValidFrom:        "2024-01-01",
+		ValidTo:          "2025-12-31",
+		RangeFrom:        990000000,
+		RangeTo:          995000000,
+	}
+	return invoice, numbering
+}
+
+func marshalSample(t *testing.T) []byte {
+	t.Helper()
+	invoice, numbering := sampleInvoice()
+	settings := Settings{Environment: "2", TechnicalKey: "fc8eac422eba16e22ffd8c6f94b3f40a6e38162c"}
+	cufe, err := CUFE(invoice, settings)
+	if err != nil {
+		t.Fatalf("CUFE: %v", err)
+	}
+	invoice.CUFE = cufe
+	doc, err := NewInvoice(invoice, numbering, settings)
+	if err != nil {
+		t.Fatalf("NewInvoice: %v", err)
+	}
+	out, err := Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return out
+}
+
+func TestInvoiceMatchesGolden(t *testing.T) {
+	got := marshalSample(t)
+	golden := filepath.Join("testdata", "invoice.golden.xml")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("no se pudo leer %s (go test ./ubl -update lo crea): %v", golden, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("el XML generado no coincide con %s:\n%s", golden, got)
+	}
+}
+
+// element es un elemento del documento con sus hijos, con el espacio de
+// nombres ya resuelto.
+type element struct {
+	Name     xml.Name
+	Children []*element
+}
+
+func parseElements(t *testing.T, data []byte) *element {
+	t.Helper()
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root *element
+	var stack []*element
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			e := &element{Name: token.Name}
+			if len(stack) == 0 {
+				root = e
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, e)
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if root == nil {
+		t.Fatal("el documento no tiene elementos")
+	}
+	return root
+}
+
+func (e *element) child(local string) *element {
+	for _, child := range e.Children {
+		if child.Name.Local == local {
+			return child
+		}
+	}
+	return nil
+}
+
+// Secuencias de UBL 2.1 (UBL-Invoice-2.1.xsd y UBL-CommonAggregateComponents-2.1.xsd),
+// con el espacio de nombres de cada elemento.
+var (
+	invoiceSequence = []xml.Name{
+		{Space: NamespaceExtensions, Local: "UBLExtensions"},
+		{Space: NamespaceBasic, Local: "UBLVersionID"},
+		{Space: NamespaceBasic, Local: "CustomizationID"},
+		{Space: NamespaceBasic, Local: "ProfileID"},
+		{Space: NamespaceBasic, Local: "ProfileExecutionID"},
+		{Space: NamespaceBasic, Local: "ID"},
+		{Space: NamespaceBasic, Local: "CopyIndicator"},
+		{Space: NamespaceBasic, Local: "UUID"},
+		{Space: NamespaceBasic, Local: "IssueDate"},
+		{Space: NamespaceBasic, Local: "IssueTime"},
+		{Space: NamespaceBasic, Local: "DueDate"},
+		{Space: NamespaceBasic, Local: "InvoiceTypeCode"},
+		{Space: NamespaceBasic, Local: "Note"},
+		{Space: NamespaceBasic, Local: "TaxPointDate"},
+		{Space: NamespaceBasic, Local: "DocumentCurrencyCode"},
+		{Space: NamespaceBasic, Local: "TaxCurrencyCode"},
+		{Space: NamespaceBasic, Local: "PricingCurrencyCode"},
+		{Space: NamespaceBasic, Local: "PaymentCurrencyCode"},
+		{Space: NamespaceBasic, Local: "PaymentAlternativeCurrencyCode"},
+		{Space: NamespaceBasic, Local: "AccountingCostCode"},
+		{Space: NamespaceBasic, Local: "AccountingCost"},
+		{Space: NamespaceBasic, Local: "LineCountNumeric"},
+		{Space: NamespaceBasic, Local: "BuyerReference"},
+		{Space: NamespaceAggregate, Local: "InvoicePeriod"},
+		{Space: NamespaceAggregate, Local: "OrderReference"},
+		{Space: NamespaceAggregate, Local: "BillingReference"},
+		{Space: NamespaceAggregate, Local: "DespatchDocumentReference"},
+		{Space: NamespaceAggregate, Local: "ReceiptDocumentReference"},
+		{Space: NamespaceAggregate, Local: "StatementDocumentReference"},
+		{Space: NamespaceAggregate, Local: "OriginatorDocumentReference"},
+		{Space: NamespaceAggregate, Local: "ContractDocumentReference"},
+		{Space: NamespaceAggregate, Local: "AdditionalDocumentReference"},
+		{Space: NamespaceAggregate, Local: "ProjectReference"},
+		{Space: NamespaceAggregate, Local: "Signature"},
+		{Space: NamespaceAggregate, Local: "AccountingSupplierParty"},
+		{Space: NamespaceAggregate, Local: "AccountingCustomerParty"},
+		{Space: NamespaceAggregate, Local: "PayeeParty"},
+		{Space: NamespaceAggregate, Local: "BuyerCustomerParty"},
+		{Space: NamespaceAggregate, Local: "SellerSupplierParty"},
+		{Space: NamespaceAggregate, Local: "TaxRepresentativeParty"},
+		{Space: NamespaceAggregate, Local: "Delivery"},
+		{Space: NamespaceAggregate, Local: "DeliveryTerms"},
+		{Space: NamespaceAggregate, Local: "PaymentMeans"},
+		{Space: NamespaceAggregate, Local: "PaymentTerms"},
+		{Space: NamespaceAggregate, Local: "PrepaidPayment"},
+		{Space: NamespaceAggregate, Local: "AllowanceCharge"},
+		{Space: NamespaceAggregate, Local: "TaxExchangeRate"},
+		{Space: NamespaceAggregate, Local: "PricingExchangeRate"},
+		{Space: NamespaceAggregate, Local: "PaymentExchangeRate"},
+		{Space: NamespaceAggregate, Local: "PaymentAlternativeExchangeRate"},
+		{Space: NamespaceAggregate, Local: "TaxTotal"},
+		{Space: NamespaceAggregate, Local: "WithholdingTaxTotal"},
+		{Space: NamespaceAggregate, Local: "LegalMonetaryTotal"},
+		{Space: NamespaceAggregate, Local: "InvoiceLine"},
+	}
+	invoiceLineSequence = []xml.Name{
+		{Space: NamespaceBasic, Local: "ID"},
+		{Space: NamespaceBasic, Local: "UUID"},
+		{Space: NamespaceBasic, Local: "Note"},
+		{Space: NamespaceBasic, Local: "InvoicedQuantity"},
+		{Space: NamespaceBasic, Local: "LineExtensionAmount"},
+		{Space: NamespaceBasic, Local: "TaxPointDate"},
+		{Space: NamespaceBasic, Local: "AccountingCostCode"},
+		{Space: NamespaceBasic, Local: "AccountingCost"},
+		{Space: NamespaceAggregate, Local: "InvoicePeriod"},
+		{Space: NamespaceAggregate, Local: "OrderLineReference"},
+		{Space: NamespaceAggregate, Local: "DespatchLineReference"},
+		{Space: NamespaceAggregate, Local: "ReceiptLineReference"},
+		{Space: NamespaceAggregate, Local: "BillingReference"},
+		{Space: NamespaceAggregate, Local: "DocumentReference"},
+		{Space: NamespaceAggregate, Local: "PricingReference"},
+		{Space: NamespaceAggregate, Local: "OriginatorParty"},
+		{Space: NamespaceAggregate, Local: "Delivery"},
+		{Space: NamespaceAggregate, Local: "PaymentTerms"},
+		{Space: NamespaceAggregate, Local: "AllowanceCharge"},
+		{Space: NamespaceAggregate, Local: "TaxTotal"},
+		{Space: NamespaceAggregate, Local: "WithholdingTaxTotal"},
+		{Space: NamespaceAggregate, Local: "Item"},
+		{Space: NamespaceAggregate, Local: "Price"},
+	}
+	legalMonetaryTotalSequence = []xml.Name{
+		{Space: NamespaceBasic, Local: "LineExtensionAmount"},
+		{Space: NamespaceBasic, Local: "TaxExclusiveAmount"},
+		{Space: NamespaceBasic, Local: "TaxInclusiveAmount"},
+		{Space: NamespaceBasic, Local: "AllowanceTotalAmount"},
+		{Space: NamespaceBasic, Local: "ChargeTotalAmount"},
+		{Space: NamespaceBasic, Local: "PrepaidAmount"},
+		{Space: NamespaceBasic, Local: "PayableRoundingAmount"},
+		{Space: NamespaceBasic, Local: "PayableAmount"},
+	}
+)
+
+// checkSequence revisa que los hijos del elemento estén en la secuencia del
+// esquema y que estén los obligatorios.
+func checkSequence(t *testing.T, parent *element, sequence []xml.Name, required ...string) {
+	t.Helper()
+	position := map[xml.Name]int{}
+	for i, name := range sequence {
+		position[name] = i
+	}
+	last := -1
+	for _, child := range parent.Children {
+		i, ok := position[child.Name]
+		if !ok {
+			t.Errorf("%s: elemento inesperado {%s}%s", parent.Name.Local, child.Name.Space, child.Name.Local)
+			continue
+		}
+		if i < last {
+			t.Errorf("%s: %s está fuera del orden del esquema", parent.Name.Local, child.Name.Local)
+		}
+		last = i
+	}
+	for _, local := range required {
+		if parent.child(local) == nil {
+			t.Errorf("%s: falta el elemento obligatorio %s", parent.Name.Local, local)
+		}
+	}
+}
+
+func TestInvoiceFollowsUBLSequence(t *testing.T) {
+	root := parseElements(t, marshalSample(t))
+	if root.Name != (xml.Name{Space: NamespaceInvoice, Local: "Invoice"}) {
+		t.Fatalf("el elemento raíz es {%s}%s", root.Name.Space, root.Name.Local)
+	}
+	checkSequence(t, root, invoiceSequence, "UBLVersionID", "ID", "IssueDate", "AccountingSupplierParty", "AccountingCustomerParty", "LegalMonetaryTotal", "InvoiceLine")
+	checkSequence(t, root.child("LegalMonetaryTotal"), legalMonetaryTotalSequence, "PayableAmount")
+
+	lines := 0
+	for _, child := range root.Children {
+		if child.Name.Local == "InvoiceLine" {
+			lines++
+			checkSequence(t, child, invoiceLineSequence, "ID", "InvoicedQuantity", "LineExtensionAmount", "Item", "Price")
+		}
+	}
+	if lines != 2 {
+		t.Errorf("el documento tiene %d líneas, se esperaban 2", lines)
+	}
+}
+
+func TestIssueDateTime(t *testing.T) {
+	tests := []struct {
+		date, wantDate, wantTime string
+	}{
+		// lib/pq devuelve la columna TIMESTAMP marcada como UTC
+		{"2024-01-15T00:00:00Z", "2024-01-15", "00:00:00-05:00"},
+		{"2024-01-15T23:30:00Z", "2024-01-15", "23:30:00-05:00"},
+		{"2024-01-15T10:00:00-05:00", "2024-01-15", "10:00:00-05:00"},
+		{"2024-01-15T10:00:00", "2024-01-15", "10:00:00-05:00"},
+		{"2024-01-15", "2024-01-15", "00:00:00-05:00"},
+	}
+	for _, tt := range tests {
+		gotDate, gotTime, err := issueDateTime(tt.date)
+		if err != nil {
+			t.Errorf("issueDateTime(%q): %v", tt.date, err)
+			continue
+		}
+		if gotDate != tt.wantDate || gotTime != tt.wantTime {
+			t.Errorf("issueDateTime(%q) = %s %s, se esperaba %s %s", tt.date, gotDate, gotTime, tt.wantDate, tt.wantTime)
+		}
+	}
+
+	for _, date := range []string{"", "15/01/2024", "2024-13-01"} {
+		if _, _, err := issueDateTime(date); err == nil {
+			t.Errorf("issueDateTime(%q) no devolvió error", date)
+		}
+	}
+}
+
+func containsLine(text, line string) bool {
+	for _, candidate := range strings.Split(text, "\n") {
+		if candidate == line {
+			return true
+		}
+	}
+	return false
+}
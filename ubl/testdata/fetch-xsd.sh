@@ -0,0 +1,21 @@
+#!/bin/sh
+# Descarga los XSD de UBL 2.1 del paquete oficial de OASIS en ubl/testdata/xsd,
+# donde los busca TestInvoiceValidatesAgainstUBLSchema. Las extensiones de la
+# DIAN van dentro de ext:ExtensionContent, que el esquema de UBL no revisa.
+set -eu
+
+cd "$(dirname "$0")"
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+curl -fsSL -o "$tmp/UBL-2.1.zip" https://docs.oasis-open.org/ubl/os-UBL-2.1/UBL-2.1.zip
+unzip -q "$tmp/UBL-2.1.zip" -d "$tmp/ubl"
+schema=$(find "$tmp/ubl" -path '*/xsd/maindoc/UBL-Invoice-2.1.xsd' | head -n 1)
+if [ -z "$schema" ]; then
+	echo "el paquete no trae xsd/maindoc/UBL-Invoice-2.1.xsd" >&2
+	exit 1
+fi
+
+rm -rf xsd
+cp -R "$(dirname "$(dirname "$schema")")" xsd
+echo "XSD de UBL 2.1 copiados en $(pwd)/xsd"
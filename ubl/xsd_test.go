@@ -0,0 +1,31 @@
+package ubl
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// ublInvoiceSchema es el esquema principal de UBL 2.1; los XSD se descargan con
+// testdata/fetch-xsd.sh.
+var ublInvoiceSchema = filepath.Join("testdata", "xsd", "maindoc", "UBL-Invoice-2.1.xsd")
+
+func TestInvoiceValidatesAgainstUBLSchema(t *testing.T) {
+	if _, err := os.Stat(ublInvoiceSchema); err != nil {
+		t.Skipf("faltan los XSD de UBL 2.1 en %s; descárgalos con ubl/testdata/fetch-xsd.sh", filepath.Dir(filepath.Dir(ublInvoiceSchema)))
+	}
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint no está instalado")
+	}
+
+	document := filepath.Join(t.TempDir(), "invoice.xml")
+	if err := os.WriteFile(document, marshalSample(t), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	output, err := exec.Command(xmllint, "--noout", "--nonet", "--schema", ublInvoiceSchema, document).CombinedOutput()
+	if err != nil {
+		t.Fatalf("el XML no cumple el esquema de UBL 2.1: %v\n%s", err, output)
+	}
+}